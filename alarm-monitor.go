@@ -0,0 +1,205 @@
+package digitemp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type AlarmEventType int
+
+const (
+	AlarmRaised AlarmEventType = iota
+	AlarmCleared
+)
+
+func (t AlarmEventType) String() string {
+	switch t {
+	case AlarmRaised:
+		return "raised"
+	case AlarmCleared:
+		return "cleared"
+	default:
+		return "unknown"
+	}
+}
+
+// Change of the sensor's alarm state.
+type AlarmEvent struct {
+	Type        AlarmEventType
+	Sensor      *TemperatureSensor
+	High        int8        // TH at the time of the event
+	Low         int8        // TL at the time of the event
+	Temperature Temperature // current temperature
+	Time        time.Time
+	Err         error // error reading sensor's temperature or alarm thresholds
+}
+
+// Monitors alarm flags of temperature sensors.
+//
+// Each cycle it runs a broadcast conversion followed by an Alarm Search. Sensor's alarm state changes only
+// after it's been the same for `debounce` sequential cycles.
+type AlarmMonitor struct {
+	bus      *UARTAdapter
+	sensors  map[ROM]*TemperatureSensor
+	debounce int
+
+	mx       sync.Mutex
+	states   map[ROM]*alarmState
+	callback func(AlarmEvent)
+	onError  func(error)
+	events   chan AlarmEvent
+}
+
+type alarmState struct {
+	active bool // debounced alarm state
+	count  int  // number of sequential cycles the flag differs from the debounced state
+}
+
+// Create alarm monitor for the sensors. Debounce is the number of sequential cycles the sensor's alarm flag
+// shall stay changed to raise or clear an alarm. It's at least 1.
+func NewAlarmMonitor(bus *UARTAdapter, sensors []*TemperatureSensor, debounce int) *AlarmMonitor {
+	if debounce < 1 {
+		debounce = 1
+	}
+	m := &AlarmMonitor{
+		bus:      bus,
+		sensors:  make(map[ROM]*TemperatureSensor, len(sensors)),
+		debounce: debounce,
+		states:   make(map[ROM]*alarmState, len(sensors)),
+	}
+	for _, s := range sensors {
+		m.sensors[*s.rom] = s
+		m.states[*s.rom] = new(alarmState)
+	}
+	return m
+}
+
+// Set function to be called on each alarm event.
+func (m *AlarmMonitor) OnEvent(callback func(AlarmEvent)) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.callback = callback
+}
+
+// Set function to be called on errors in `Run()`. If it's set, monitoring continues after errors.
+func (m *AlarmMonitor) OnError(callback func(error)) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.onError = callback
+}
+
+// Get channel alarm events are delivered to. The channel shall be drained, otherwise monitoring blocks.
+func (m *AlarmMonitor) Events() <-chan AlarmEvent {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if m.events == nil {
+		m.events = make(chan AlarmEvent, len(m.sensors))
+	}
+	return m.events
+}
+
+// Check if sensor's alarm is raised.
+func (m *AlarmMonitor) IsAlarmRaised(sensor *TemperatureSensor) bool {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	if st, ok := m.states[*sensor.rom]; ok {
+		return st.active
+	}
+	return false
+}
+
+// Run monitoring cycle every interval until context is done.
+// Returns context's error or, if no error callback is set, the first monitoring error.
+func (m *AlarmMonitor) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(); err != nil {
+			m.mx.Lock()
+			onError := m.onError
+			m.mx.Unlock()
+			if onError == nil {
+				return err
+			}
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run single monitoring cycle: measure temperature, search for alarming sensors and deliver events.
+func (m *AlarmMonitor) Poll() error {
+	if _, err := m.bus.MeasureTemperatureAll(); err != nil {
+		return err
+	}
+	roms, err := m.bus.GetROMsWithAlarm()
+	if err != nil {
+		return err
+	}
+	alarming := make(map[ROM]bool, len(roms))
+	for _, rom := range roms {
+		alarming[*rom] = true
+	}
+
+	for rom, sensor := range m.sensors {
+		if !m.update(rom, alarming[rom]) {
+			continue
+		}
+		event := AlarmEvent{
+			Type:   AlarmCleared,
+			Sensor: sensor,
+			Time:   time.Now(),
+		}
+		if alarming[rom] {
+			event.Type = AlarmRaised
+		}
+		if event.Temperature, event.Err = sensor.ReadTemperatureValue(); event.Err == nil {
+			event.High, event.Low, event.Err = sensor.GetAlarms()
+		}
+		m.deliver(event)
+	}
+	return nil
+}
+
+// Update debounced alarm state with the current alarm flag. Returns true if the state changed.
+func (m *AlarmMonitor) update(rom ROM, flag bool) bool {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	st := m.states[rom]
+	if flag == st.active {
+		st.count = 0
+		return false
+	}
+	st.count++
+	if st.count < m.debounce {
+		return false
+	}
+	st.active = flag
+	st.count = 0
+	return true
+}
+
+func (m *AlarmMonitor) deliver(event AlarmEvent) {
+	m.mx.Lock()
+	callback := m.callback
+	events := m.events
+	m.mx.Unlock()
+
+	if callback != nil {
+		callback(event)
+	}
+	if events != nil {
+		events <- event
+	}
+}
@@ -0,0 +1,122 @@
+package digitemp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAlarmMonitor_update(t *testing.T) {
+	rom, _ := NewROMFromString("10A75CA80208001A")
+	sensor := &TemperatureSensor{rom: rom}
+	m := NewAlarmMonitor(nil, []*TemperatureSensor{sensor}, 2)
+
+	var testcases = []struct {
+		flag    bool
+		changed bool
+		active  bool
+	}{
+		{true, false, false},
+		{false, false, false}, // glitch
+		{true, false, false},
+		{true, true, true}, // raised
+		{true, false, true},
+		{false, false, true},
+		{false, true, false}, // cleared
+	}
+	for n, tc := range testcases {
+		if changed := m.update(*rom, tc.flag); changed != tc.changed {
+			t.Errorf("(%d, changed: %t, expected: %t)", n, changed, tc.changed)
+		}
+		if active := m.IsAlarmRaised(sensor); active != tc.active {
+			t.Errorf("(%d, active: %t, expected: %t)", n, active, tc.active)
+		}
+	}
+}
+
+func TestAlarmMonitor_Poll(t *testing.T) {
+	a := newFakeThermometer("2811223344556656")
+	b := newFakeThermometer("3B112233445566EA")
+	a.scratchpad[2], a.scratchpad[3] = 30, 10
+	b.scratchpad[2], b.scratchpad[3] = 30, 10
+	bus := newFakeBus(a.fakeDevice, b.fakeDevice)
+	sa, sb := newFakeSensor(bus, a), newFakeSensor(bus, b)
+
+	m := NewAlarmMonitor(bus, []*TemperatureSensor{sa, sb}, 1)
+	var called []AlarmEvent
+	m.OnEvent(func(event AlarmEvent) {
+		called = append(called, event)
+	})
+	events := m.Events()
+
+	poll := func(expected ...AlarmEventType) {
+		t.Helper()
+		called = nil
+		if err := m.Poll(); err != nil {
+			t.Fatal(err)
+		}
+		if len(called) != len(expected) || len(events) != len(expected) {
+			t.Fatalf("got %d callbacks and %d channel events, expected: %d", len(called), len(events), len(expected))
+		}
+		for n, typ := range expected {
+			event := <-events
+			if event != called[n] {
+				t.Errorf("channel event differs from callback: %+v", event)
+			}
+			if event.Type != typ || event.Sensor != sa || event.Err != nil {
+				t.Errorf("got: %+v, expected %s event of %s", event, typ, sa.rom)
+			}
+			if event.High != 30 || event.Low != 10 || event.Temperature != Temperature(int16(a.raw))*625 {
+				t.Errorf("got: %d/%d, %v", event.High, event.Low, event.Temperature)
+			}
+		}
+	}
+
+	poll()
+	a.raw = 31 * 16
+	poll(AlarmRaised)
+	if !m.IsAlarmRaised(sa) || m.IsAlarmRaised(sb) {
+		t.Error("wrong alarm states")
+	}
+	poll()
+	a.raw = 20 * 16
+	poll(AlarmCleared)
+	if m.IsAlarmRaised(sa) {
+		t.Error("alarm is not cleared")
+	}
+	if a.conversions != 4 {
+		t.Errorf("got %d conversions, expected: 4", a.conversions)
+	}
+}
+
+func TestAlarmMonitor_Run(t *testing.T) {
+	a := newFakeThermometer("2811223344556656")
+	a.scratchpad[2], a.scratchpad[3] = 20, 10
+	bus := newFakeBus(a.fakeDevice)
+	sa := newFakeSensor(bus, a)
+
+	m := NewAlarmMonitor(bus, []*TemperatureSensor{sa}, 2)
+	events := m.Events()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- m.Run(ctx, time.Millisecond)
+	}()
+
+	select {
+	case event := <-events:
+		if event.Type != AlarmRaised || event.Sensor != sa {
+			t.Errorf("got: %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("no alarm event delivered")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got: %v, expected: %v", err, context.Canceled)
+	}
+	if a.conversions < 2 {
+		t.Errorf("alarm raised after %d conversions, debounce is 2", a.conversions)
+	}
+}
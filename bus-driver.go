@@ -18,6 +18,7 @@ package digitemp
 // Using an UART to Implement a 1-Wire Bus Master (http://www.maximintegrated.com/en/app-notes/index.mvp/id/214)
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"go.bug.st/serial"
@@ -30,6 +31,8 @@ type UARTAdapter struct {
 	uart   serial.Port
 	mode   serial.Mode
 	mx     sync.Mutex
+
+	sensors []*TemperatureSensor // temperature sensors created on this bus
 }
 
 func NewUartAdapter(device string) (*UARTAdapter, error) {
@@ -91,20 +94,38 @@ func (a *UARTAdapter) IsConnected(rom *ROM) (bool, error) {
 
 // This command initiates a single temperature conversion for all connected temperature sensors at once.
 // After this command you can read temperature from each sensor using `sensor.ReadTemperature()`.
-func (a *UARTAdapter) MeasureTemperatureAll() error {
+//
+// If any device on the bus is in parasitic mode, it waits for T_conv of the slowest known sensor.
+// Otherwise, it polls the bus until all devices finish the conversion.
+// Returns the actual conversion time.
+func (a *UARTAdapter) MeasureTemperatureAll() (time.Duration, error) {
 	a.lock()
 	defer a.unlock()
 
+	return a.convertAll()
+}
+
+// Initiate temperature conversion on all devices and wait for it to finish.
+func (a *UARTAdapter) convertAll() (time.Duration, error) {
 	if err := a.skipROM(); err != nil {
-		return err
+		return 0, err
+	}
+	parasiticMode, err := a.readPowerSupply()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.skipROM(); err != nil {
+		return 0, err
 	}
 	if err := a.writeByte(0x44); err != nil {
-		return err
+		return 0, err
 	}
-	// We do not know if there are any DS18B20 or DS1822 on the line and what are their resolution settings.
-	// So, we just wait max(T_conv) that is 750ms for currently supported devices.
-	time.Sleep(750 * time.Millisecond)
-	return nil
+	startedAt := time.Now()
+	if err := a.wait(a.maxConversionTime(), parasiticMode); err != nil {
+		return 0, err
+	}
+	return time.Since(startedAt), nil
 }
 
 // Close serial port.
@@ -168,6 +189,56 @@ func (a *UARTAdapter) reset() error {
 	return pulseErr
 }
 
+// Wait for specified time in parasitic mode or until operation is finished in external power mode.
+func (a *UARTAdapter) wait(duration time.Duration, parasiticMode bool) error {
+	if parasiticMode {
+		time.Sleep(duration)
+		return nil
+	}
+	startedAt := time.Now()
+	for {
+		if b, err := a.readBit(); err != nil {
+			return err
+		} else {
+			if b != 0b0 {
+				break
+			}
+		}
+		if time.Since(startedAt) > duration {
+			break
+		}
+	}
+	return nil
+}
+
+// Returns T_conv of the slowest temperature sensor known on the bus.
+// We do not know what devices are on the line and what are their resolution settings if there are no sensors
+// created yet. So, it falls back to max(T_conv) that is 750ms for currently supported devices.
+func (a *UARTAdapter) maxConversionTime() time.Duration {
+	if len(a.sensors) == 0 {
+		return 750 * time.Millisecond
+	}
+	var tConv time.Duration
+	for _, s := range a.sensors {
+		if s.tConv > tConv {
+			tConv = s.tConv
+		}
+	}
+	return tConv
+}
+
+// Remember the sensor for bus-wide measurements. A sensor created again for the same ROM replaces
+// the previous instance, so each device is waited for only once.
+func (a *UARTAdapter) addSensor(s *TemperatureSensor) {
+	for n, sensor := range a.sensors {
+		if *sensor.rom == *s.rom {
+			a.sensors[n] = s
+			return
+		}
+	}
+	a.sensors = append(a.sensors, s)
+}
+
 // Discards data in input/output buffers
 func (a *UARTAdapter) clear() error {
 	if err := a.uart.ResetOutputBuffer(); err != nil {
@@ -459,7 +530,9 @@ func (a *UARTAdapter) searchROM(WithAlarm bool) ([]*ROM, error) {
 				}
 			}
 		}
-		complete = append(complete, newRomFromBits(current))
+		if len(current) == 64 {
+			complete = append(complete, newRomFromBits(current))
+		}
 		if len(partials) == 0 {
 			break
 		}
@@ -469,6 +542,47 @@ func (a *UARTAdapter) searchROM(WithAlarm bool) ([]*ROM, error) {
 	return complete, nil
 }
 
+//
+// READ POWER SUPPLY [B4h]
+//
+// The bus driver issues this command (after addressing devices) to determine if devices on the bus
+// are using parasite power. Returns true if any of the addressed devices pull the bus low.
+//
+func (a *UARTAdapter) readPowerSupply() (bool, error) {
+	if err := a.writeByte(0xb4); err != nil {
+		return false, err
+	}
+	if pm, err := a.readBit(); err != nil {
+		return false, err
+	} else {
+		return pm == 0b0, nil
+	}
+}
+
+//
+// READ SCRATCHPAD [BEh]
+//
+// Reads 8 bytes of the addressed device's scratchpad followed by CRC.
+// Data is returned along with CRC error, so the caller may inspect it.
+// All ones, CRC included, means nobody drives the bus and is reported as ErrBogusReading.
+//
+func (a *UARTAdapter) readScratchpad() ([]byte, error) {
+	if err := a.writeByte(0xbe); err != nil {
+		return nil, err
+	}
+	var data = make([]byte, 9)
+	if _, err := a.readBytes(data); err != nil {
+		return nil, err
+	}
+	if bytes.Count(data, []byte{0xff}) == 9 {
+		return data, ErrBogusReading
+	}
+	if crc8(data[0:8]) != data[8] {
+		return data, errors.New("scratchpad crc error")
+	}
+	return data, nil
+}
+
 func (a *UARTAdapter) isConnected(rom *ROM) (bool, error) {
 	if err := a.reset(); err != nil {
 		return false, err
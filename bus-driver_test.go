@@ -0,0 +1,199 @@
+package digitemp
+
+import (
+	"go.bug.st/serial"
+)
+
+// Serial port emulating 1-Wire bus with devices connected to it.
+// Reset is written at 9600 baud, every other byte written is a time slot.
+type fakePort struct {
+	baudRate int
+	devices  []*fakeDevice
+	rx       []byte
+	writes   int // resets and time slots
+}
+
+func newFakeBus(devices ...*fakeDevice) *UARTAdapter {
+	return &UARTAdapter{
+		device: "fake",
+		uart:   &fakePort{devices: devices},
+		mode:   serial.Mode{BaudRate: 115200},
+	}
+}
+
+func (p *fakePort) SetMode(mode *serial.Mode) error {
+	p.baudRate = mode.BaudRate
+	return nil
+}
+
+func (p *fakePort) Write(data []byte) (int, error) {
+	for _, b := range data {
+		p.writes++
+		if p.baudRate == 9600 {
+			presence := false
+			for _, d := range p.devices {
+				presence = d.reset() || presence
+			}
+			if presence {
+				p.rx = append(p.rx, 0xe0)
+			} else {
+				p.rx = append(p.rx, 0xf0)
+			}
+			continue
+		}
+		var bit byte = 0
+		if b == 0xff {
+			bit = 1
+		}
+		for _, d := range p.devices {
+			bit &= d.slot(bit)
+		}
+		p.rx = append(p.rx, bit*0xff)
+	}
+	return len(data), nil
+}
+
+func (p *fakePort) Read(data []byte) (int, error) {
+	n := copy(data, p.rx)
+	p.rx = p.rx[n:]
+	return n, nil
+}
+
+func (p *fakePort) ResetInputBuffer() error {
+	p.rx = nil
+	return nil
+}
+
+func (p *fakePort) ResetOutputBuffer() error { return nil }
+func (p *fakePort) SetDTR(bool) error        { return nil }
+func (p *fakePort) SetRTS(bool) error        { return nil }
+func (p *fakePort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{}, nil
+}
+func (p *fakePort) Close() error { return nil }
+
+// States of fake device's ROM and function layers.
+const (
+	fakeIdle = iota
+	fakeROMCommand
+	fakeMatchROM
+	fakeSearchROM
+	fakeFunction
+	fakeData
+)
+
+// 1-Wire slave handling ROM commands. Function commands are passed to onFunction.
+type fakeDevice struct {
+	rom        ROM
+	absent     bool
+	alarm      bool
+	onFunction func(d *fakeDevice, command byte)
+
+	state     int
+	tx        []byte // bits to send
+	in        byte
+	inBits    int
+	data      []byte
+	need      int
+	onData    func(data []byte)
+	searchBit int
+}
+
+func (d *fakeDevice) reset() bool {
+	d.tx, d.in, d.inBits, d.data = nil, 0, 0, nil
+	if d.absent {
+		d.state = fakeIdle
+		return false
+	}
+	d.state = fakeROMCommand
+	return true
+}
+
+// Send bytes to the master, least significant bit first.
+func (d *fakeDevice) send(data ...byte) {
+	for _, b := range data {
+		for n := 0; n < 8; n++ {
+			d.tx = append(d.tx, (b>>n)&0b1)
+		}
+	}
+}
+
+// Receive n bytes and pass them to the handler.
+func (d *fakeDevice) expect(n int, handler func(data []byte)) {
+	d.state, d.data, d.need, d.onData = fakeData, nil, n, handler
+}
+
+func (d *fakeDevice) romBit(n int) byte {
+	return (d.rom.Code[n/8] >> (n % 8)) & 0b1
+}
+
+func (d *fakeDevice) slot(bit byte) byte {
+	if len(d.tx) > 0 {
+		b := d.tx[0]
+		d.tx = d.tx[1:]
+		return bit & b
+	}
+	switch d.state {
+	case fakeIdle:
+		return bit
+	case fakeSearchROM:
+		if bit != d.romBit(d.searchBit) {
+			d.state = fakeIdle
+		} else if d.searchBit++; d.searchBit == 64 {
+			d.state = fakeFunction
+		} else {
+			d.tx = []byte{d.romBit(d.searchBit), d.romBit(d.searchBit) ^ 1}
+		}
+		return bit
+	}
+
+	d.in |= bit << d.inBits
+	if d.inBits++; d.inBits == 8 {
+		b := d.in
+		d.in, d.inBits = 0, 0
+		d.receive(b)
+	}
+	return bit
+}
+
+func (d *fakeDevice) receive(b byte) {
+	switch d.state {
+	case fakeROMCommand:
+		switch b {
+		case 0x55:
+			d.state, d.data = fakeMatchROM, nil
+		case 0xcc:
+			d.state = fakeFunction
+		case 0x33:
+			d.state = fakeFunction
+			d.send(d.rom.Code[:]...)
+		case 0xf0, 0xec:
+			if b == 0xec && !d.alarm {
+				d.state = fakeIdle
+				return
+			}
+			d.state, d.searchBit = fakeSearchROM, 0
+			d.tx = []byte{d.romBit(0), d.romBit(0) ^ 1}
+		default:
+			d.state = fakeIdle
+		}
+	case fakeMatchROM:
+		if d.data = append(d.data, b); len(d.data) == 8 {
+			if *NewROMFromBytes(d.data) == d.rom {
+				d.state = fakeFunction
+			} else {
+				d.state = fakeIdle
+			}
+		}
+	case fakeFunction:
+		d.state = fakeIdle
+		if d.onFunction != nil {
+			d.onFunction(d, b)
+		}
+	case fakeData:
+		if d.data = append(d.data, b); len(d.data) == d.need {
+			d.state = fakeIdle
+			d.onData(d.data)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package digitemp
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+// Linear correction of sensor readings: corrected = raw * Gain + Offset
+type Calibration struct {
+	Gain   float64 `json:"gain"`
+	Offset float64 `json:"offset"` // ºC
+}
+
+// Raw sensor reading taken along with the reference thermometer reading.
+type ReferenceReading struct {
+	Raw       Temperature `json:"raw"`
+	Reference Temperature `json:"reference"`
+}
+
+// Create calibration which only shifts readings by offset in ºC.
+func NewOffsetCalibration(offset float64) *Calibration {
+	return &Calibration{
+		Gain:   1,
+		Offset: offset,
+	}
+}
+
+// Create calibration from readings at two reference points, e.g. ice and boiling water.
+func NewTwoPointCalibration(rawLow, refLow, rawHigh, refHigh Temperature) (*Calibration, error) {
+	if rawLow == rawHigh {
+		return nil, errors.New("reference points must differ")
+	}
+	gain := (refHigh.Celsius() - refLow.Celsius()) / (rawHigh.Celsius() - rawLow.Celsius())
+	return &Calibration{
+		Gain:   gain,
+		Offset: refLow.Celsius() - rawLow.Celsius()*gain,
+	}, nil
+}
+
+// Compute calibration from reference readings.
+// Single reading gives offset calibration. Two or more readings give linear correction fitted
+// with least squares, which is the same as two-point calibration in case of two readings.
+func ComputeCalibration(readings []ReferenceReading) (*Calibration, error) {
+	switch len(readings) {
+	case 0:
+		return nil, errors.New("no reference readings")
+	case 1:
+		return NewOffsetCalibration(readings[0].Reference.Celsius() - readings[0].Raw.Celsius()), nil
+	}
+
+	var sumX, sumY, sumXX, sumXY float64
+	for _, r := range readings {
+		x, y := r.Raw.Celsius(), r.Reference.Celsius()
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	n := float64(len(readings))
+	d := n*sumXX - sumX*sumX
+	if d == 0 {
+		return nil, errors.New("reference readings must differ")
+	}
+	gain := (n*sumXY - sumX*sumY) / d
+	return &Calibration{
+		Gain:   gain,
+		Offset: (sumY - gain*sumX) / n,
+	}, nil
+}
+
+// Apply calibration to raw reading.
+func (c *Calibration) Apply(raw Temperature) Temperature {
+	if c == nil {
+		return raw
+	}
+	return NewTemperatureFromCelsius(raw.Celsius()*c.Gain + c.Offset)
+}
+
+// Calibrations of sensors by their ROM codes.
+type CalibrationProfiles map[ROM]*Calibration
+
+// Load calibration profiles from JSON file.
+func LoadCalibrationProfiles(path string) (CalibrationProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(CalibrationProfiles)
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Save calibration profiles to JSON file.
+func (p CalibrationProfiles) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Set calibration of every sensor which has a profile.
+func (p CalibrationProfiles) Apply(sensors []*TemperatureSensor) {
+	for _, s := range sensors {
+		if c, ok := p[*s.rom]; ok {
+			s.SetCalibration(c)
+		}
+	}
+}
+
+// Set calibration applied to the sensor's readings. Pass nil to get raw readings.
+func (s *TemperatureSensor) SetCalibration(c *Calibration) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	s.calibration = c
+}
+
+func (s *TemperatureSensor) GetCalibration() *Calibration {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	return s.calibration
+}
+
+// Read temperature from scratchpad without measuring and calibration.
+// Returns temperature in full sensor's resolution
+func (s *TemperatureSensor) ReadTemperatureRaw() (Temperature, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	m := s.newMeasurement()
+	s.readMeasurement(m)
+	return m.RawTemperature, m.Err
+}
@@ -0,0 +1,74 @@
+package digitemp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTwoPointCalibration(t *testing.T) {
+	// sensor reads 0.5 at ice and 99.0 at boiling water
+	c, err := NewTwoPointCalibration(5000, 0, 990000, 1000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var testcases = []struct {
+		raw       Temperature
+		corrected Temperature
+	}{
+		{5000, 0},
+		{990000, 1000000},
+		{497500, 500000},
+	}
+	for n, tc := range testcases {
+		if v := c.Apply(tc.raw); v != tc.corrected {
+			t.Errorf("(%d, got: %s, expected: %s)", n, v, tc.corrected)
+		}
+	}
+	if _, err := NewTwoPointCalibration(5000, 0, 5000, 1000000); err == nil {
+		t.Error("same points accepted")
+	}
+}
+
+func TestComputeCalibration(t *testing.T) {
+	c, err := ComputeCalibration([]ReferenceReading{{Raw: 250625, Reference: 253125}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Gain != 1 || c.Apply(0) != 2500 {
+		t.Errorf("got: %+v", c)
+	}
+
+	c, err = ComputeCalibration([]ReferenceReading{
+		{Raw: 5000, Reference: 0},
+		{Raw: 497500, Reference: 500000},
+		{Raw: 990000, Reference: 1000000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := c.Apply(990000); v != 1000000 {
+		t.Errorf("got: %s", v)
+	}
+	var nilCalibration *Calibration
+	if v := nilCalibration.Apply(250625); v != 250625 {
+		t.Errorf("got: %s", v)
+	}
+}
+
+func TestCalibrationProfiles_Save(t *testing.T) {
+	rom, _ := NewROMFromString("10A75CA80208001A")
+	profiles := CalibrationProfiles{
+		*rom: {Gain: 1.01, Offset: -0.25},
+	}
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	if err := profiles.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCalibrationProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := loaded[*rom]; !ok || *c != *profiles[*rom] {
+		t.Errorf("got: %v", loaded)
+	}
+}
@@ -0,0 +1,163 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrSensorMissing = errors.New("sensor is missing on the bus")
+
+// Tracks temperature of many sensors reading only those which changed since the last cycle.
+//
+// It programs TH/TL of each sensor to a window around its last value. Then each cycle does one broadcast
+// conversion and an Alarm Search and reads only the sensors whose temperature left their window.
+// The windows are kept in scratchpad and not copied to EEPROM to save it from wear.
+//
+// A sensor which restarted after power loss reloads TH/TL from EEPROM and may never leave that window,
+// so every few cycles all sensors are read and re-armed. Sensors missing on the bus are reported
+// and their values are dropped until a refresh cycle finds them back.
+//
+// Note, that TH/TL are used for windows, so alarm monitoring and user tags can't be used at the same time.
+type ChangeTracker struct {
+	bus     *UARTAdapter
+	sensors map[ROM]*TemperatureSensor
+	window  int
+	refresh int // every n-th cycle reads all sensors
+	polls   int
+
+	mx      sync.Mutex
+	values  map[ROM]Temperature
+	armed   map[ROM]bool // window is programmed around the last value
+	missing map[ROM]bool
+}
+
+// Create change tracker for the sensors. Window is the change in whole ºC which makes the sensor to be read.
+// It's at least 1. All sensors are read every 10th cycle, see SetRefreshInterval.
+func NewChangeTracker(bus *UARTAdapter, sensors []*TemperatureSensor, window int) *ChangeTracker {
+	if window < 1 {
+		window = 1
+	}
+	c := &ChangeTracker{
+		bus:     bus,
+		sensors: make(map[ROM]*TemperatureSensor, len(sensors)),
+		window:  window,
+		refresh: 10,
+		values:  make(map[ROM]Temperature, len(sensors)),
+		armed:   make(map[ROM]bool, len(sensors)),
+		missing: make(map[ROM]bool, len(sensors)),
+	}
+	for _, s := range sensors {
+		c.sensors[*s.rom] = s
+	}
+	return c
+}
+
+// Read all sensors every n-th cycle regardless of their alarm flags. 0 disables it.
+func (c *ChangeTracker) SetRefreshInterval(n int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.refresh = n
+}
+
+// Run single tracking cycle. Returns sensors which were read during the cycle.
+// Sensors failed to be read are retried next cycle, the error of the last one is returned.
+//
+// A quiet cycle costs only the conversion and an Alarm Search. The bus isn't searched for missing sensors:
+// a sensor which fails to be read is checked for presence and reported with ErrSensorMissing.
+// Armed sensors are silent in Alarm Search, so their loss shows up on the next refresh cycle,
+// which also retries missing ones.
+func (c *ChangeTracker) Poll() ([]*TemperatureSensor, error) {
+	if _, err := c.bus.MeasureTemperatureAll(); err != nil {
+		return nil, err
+	}
+	roms, err := c.bus.GetROMsWithAlarm()
+	if err != nil {
+		return nil, err
+	}
+	alarming := make(map[ROM]bool, len(roms))
+	for _, rom := range roms {
+		alarming[*rom] = true
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.polls++
+	refresh := c.refresh > 0 && c.polls%c.refresh == 0
+
+	var lastErr error
+	updated := make([]*TemperatureSensor, 0, len(roms))
+	for rom, sensor := range c.sensors {
+		if !refresh && (c.missing[rom] || c.armed[rom] && !alarming[rom]) {
+			continue
+		}
+		c.armed[rom] = false
+		t, err := sensor.ReadTemperatureValue()
+		if err != nil {
+			if online, cerr := c.bus.IsConnected(sensor.rom); cerr == nil && !online {
+				c.missing[rom] = true
+				delete(c.values, rom)
+				err = ErrSensorMissing
+			}
+			lastErr = fmt.Errorf("%s: %w", sensor.rom, err)
+			continue
+		}
+		delete(c.missing, rom)
+		c.values[rom] = t
+		updated = append(updated, sensor)
+
+		high, low := alarmWindow(t, c.window)
+		if err := sensor.SetAlarms(high, low); err != nil {
+			lastErr = fmt.Errorf("%s: %w", sensor.rom, err)
+			continue
+		}
+		c.armed[rom] = true
+	}
+	return updated, lastErr
+}
+
+// Get sensors found missing on the bus and not back yet.
+func (c *ChangeTracker) Missing() []*TemperatureSensor {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	missing := make([]*TemperatureSensor, 0, len(c.missing))
+	for rom := range c.missing {
+		missing = append(missing, c.sensors[rom])
+	}
+	return missing
+}
+
+// Get last known temperature of all sensors read at least once and present on the bus.
+func (c *ChangeTracker) Values() map[ROM]Temperature {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	values := make(map[ROM]Temperature, len(c.values))
+	for rom, t := range c.values {
+		values[rom] = t
+	}
+	return values
+}
+
+// Calculate TH and TL around the temperature.
+// Sensors compare TH and TL with the integer part of the temperature and set alarm flag
+// if it's TH or higher, or TL or lower.
+func alarmWindow(t Temperature, window int) (int8, int8) {
+	degrees := int(t) / temperatureScale
+	if t < 0 && int(t)%temperatureScale != 0 {
+		degrees-- // floor
+	}
+	clamp := func(v int) int8 {
+		if v > 127 {
+			return 127
+		}
+		if v < -128 {
+			return -128
+		}
+		return int8(v)
+	}
+	return clamp(degrees + window), clamp(degrees - window)
+}
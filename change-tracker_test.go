@@ -0,0 +1,150 @@
+package digitemp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAlarmWindow(t *testing.T) {
+	var testcases = []struct {
+		temperature Temperature
+		window      int
+		high        int8
+		low         int8
+	}{
+		{250625, 1, 26, 24},
+		{0, 1, 1, -1},
+		{-625, 1, 0, -2},
+		{-250000, 2, -23, -27},
+		{1250000, 5, 127, 120},
+	}
+	for n, tc := range testcases {
+		if high, low := alarmWindow(tc.temperature, tc.window); high != tc.high || low != tc.low {
+			t.Errorf("(%d, got: %d/%d, expected: %d/%d)", n, high, low, tc.high, tc.low)
+		}
+	}
+}
+
+func TestChangeTracker_Poll(t *testing.T) {
+	a := newFakeThermometer("2811223344556656")
+	b := newFakeThermometer("3B112233445566EA")
+	a.eeprom = [3]byte{0x7d, 0xc9, 0x7f} // 125/-55, never triggers
+	bus := newFakeBus(a.fakeDevice, b.fakeDevice)
+	sa, sb := newFakeSensor(bus, a), newFakeSensor(bus, b)
+
+	tracker := NewChangeTracker(bus, []*TemperatureSensor{sa, sb}, 1)
+	tracker.SetRefreshInterval(3)
+
+	poll := func(expected ...*TemperatureSensor) error {
+		t.Helper()
+		updated, err := tracker.Poll()
+		if len(updated) != len(expected) {
+			t.Errorf("poll %d: updated %d sensors, expected: %d", tracker.polls, len(updated), len(expected))
+		}
+		for n := range expected {
+			found := false
+			for _, s := range updated {
+				found = found || s == expected[n]
+			}
+			if !found {
+				t.Errorf("poll %d: sensor %s not updated", tracker.polls, expected[n].rom)
+			}
+		}
+		return err
+	}
+
+	// 1: all sensors are read and armed, 2: nothing changed
+	if err := poll(sa, sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 3: full refresh
+	if err := poll(sa, sb); err != nil {
+		t.Fatal(err)
+	}
+
+	// 4: change leaves the window
+	a.raw += 2 * 16
+	if err := poll(sa); err != nil {
+		t.Fatal(err)
+	}
+
+	// 5: power-on reset loads window which never triggers, 6: full refresh reads it
+	a.powerOnReset()
+	a.raw += 5 * 16
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(sa, sb); err != nil {
+		t.Fatal(err)
+	}
+	if v := tracker.Values()[a.rom]; v != 320625 {
+		t.Errorf("got: %v, expected: 32.0625ºC", v)
+	}
+
+	// 7, 8: armed sensor is gone, it's silent until 9: full refresh
+	b.absent = true
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(sa); !errors.Is(err, ErrSensorMissing) {
+		t.Errorf("got: %v, expected: %v", err, ErrSensorMissing)
+	}
+	if _, ok := tracker.Values()[b.rom]; ok {
+		t.Error("value of missing sensor is kept")
+	}
+	if missing := tracker.Missing(); len(missing) != 1 || missing[0] != sb {
+		t.Errorf("missing: %v", missing)
+	}
+
+	// 10, 11: missing sensor is back, but retried only by 12: full refresh
+	b.absent = false
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := poll(sa, sb); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracker.Missing()) != 0 {
+		t.Error("sensor is still missing")
+	}
+}
+
+func TestChangeTracker_PollQuiet(t *testing.T) {
+	var devices []*fakeDevice
+	var sensors []*TemperatureSensor
+	bus := newFakeBus()
+	for n := 0; n < 10; n++ {
+		therm := newFakeThermometer("2811223344556656")
+		therm.rom.Code[6] = byte(n)
+		therm.rom.Code[7] = crc8(therm.rom.Code[:7])
+		devices = append(devices, therm.fakeDevice)
+		sensors = append(sensors, newFakeSensor(bus, therm))
+	}
+	port := bus.uart.(*fakePort)
+	port.devices = devices
+
+	tracker := NewChangeTracker(bus, sensors, 1)
+	if _, err := tracker.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a single scratchpad read takes reset, 64 bits of ROM and 88 bits of command and data,
+	// a quiet cycle shall cost less than that regardless of the number of sensors
+	port.writes = 0
+	if updated, err := tracker.Poll(); err != nil || len(updated) != 0 {
+		t.Fatalf("updated: %d, error: %v", len(updated), err)
+	}
+	if port.writes > 153 {
+		t.Errorf("quiet cycle took %d bus operations", port.writes)
+	}
+}
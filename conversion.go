@@ -0,0 +1,94 @@
+package digitemp
+
+import (
+	"time"
+)
+
+// Temperature conversion started by `sensor.StartConversion()`.
+type Conversion struct {
+	sensor    *TemperatureSensor
+	startedAt time.Time
+	readyAt   time.Time
+}
+
+// Initiate temperature conversion and return without waiting for it to finish.
+//
+// The bus is released during the conversion, so other devices on the same bus can be used meanwhile.
+// Use `conversion.ReadWhenReady()` to read the temperature once it's converted.
+//
+// In parasitic mode the sensor is powered from the bus during the conversion and any traffic would
+// starve it. So, the bus is kept locked until the conversion is finished and the returned conversion
+// is ready at once.
+func (s *TemperatureSensor) StartConversion() (*Conversion, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	c := &Conversion{
+		sensor:    s,
+		startedAt: time.Now(),
+	}
+	if s.parasiticMode {
+		if err := s.convertT(); err != nil {
+			return nil, err
+		}
+		c.readyAt = time.Now()
+		return c, nil
+	}
+
+	if err := s.reset(); err != nil {
+		return nil, err
+	}
+	if err := s.bus.writeByte(0x44); err != nil {
+		return nil, err
+	}
+	c.readyAt = c.startedAt.Add(s.tConv)
+	return c, nil
+}
+
+// Get the sensor the conversion was started on.
+func (c *Conversion) GetSensor() *TemperatureSensor {
+	return c.sensor
+}
+
+// Get time the conversion was started at.
+func (c *Conversion) StartedAt() time.Time {
+	return c.startedAt
+}
+
+// Get time the conversion is guaranteed to be finished at.
+func (c *Conversion) ReadyAt() time.Time {
+	return c.readyAt
+}
+
+// Check the conversion is finished.
+func (c *Conversion) IsReady() bool {
+	return !time.Now().Before(c.readyAt)
+}
+
+// Block until the conversion is finished.
+func (c *Conversion) Await() {
+	if d := time.Until(c.readyAt); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// Wait for the conversion to finish and read temperature from scratchpad.
+// Returns temperature * 100 in ºC as int
+func (c *Conversion) ReadWhenReady() (int, error) {
+	c.Await()
+	return c.sensor.ReadTemperature()
+}
+
+// Wait for the conversion to finish and read temperature from scratchpad.
+// Returns temperature in ºC as float
+func (c *Conversion) ReadWhenReadyFloat() (float32, error) {
+	c.Await()
+	return c.sensor.ReadTemperatureFloat()
+}
+
+// Wait for the conversion to finish and read temperature from scratchpad.
+// Returns temperature in full sensor's resolution
+func (c *Conversion) ReadWhenReadyValue() (Temperature, error) {
+	c.Await()
+	return c.sensor.ReadTemperatureValue()
+}
@@ -0,0 +1,87 @@
+package digitemp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConversion_Powered(t *testing.T) {
+	therm := newFakeThermometer("2811223344556656")
+	other := newFakeThermometer("2811223344556657")
+	other.rom.Code[7] = crc8(other.rom.Code[:7])
+	bus := newFakeBus(therm.fakeDevice, other.fakeDevice)
+	s := newFakeSensor(bus, therm)
+	s.tConv = 50 * time.Millisecond
+
+	c, err := s.StartConversion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if therm.conversions != 1 {
+		t.Fatalf("got %d conversions, expected: 1", therm.conversions)
+	}
+	if c.ReadyAt().Sub(c.StartedAt()) != s.tConv {
+		t.Errorf("ready after %v, expected: %v", c.ReadyAt().Sub(c.StartedAt()), s.tConv)
+	}
+	if c.IsReady() {
+		t.Error("conversion ready at once")
+	}
+
+	// the bus is free for other devices during the conversion
+	if _, err := newFakeSensor(bus, other).ReadTemperature(); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsReady() {
+		t.Error("bus was kept locked during the conversion")
+	}
+
+	// reading before the ready time waits for the conversion
+	temperature, err := c.ReadWhenReady()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Now().Before(c.ReadyAt()) {
+		t.Error("read before the conversion is ready")
+	}
+	if temperature != 2506 {
+		t.Errorf("got: %d, expected: 2506", temperature)
+	}
+}
+
+func TestConversion_Parasitic(t *testing.T) {
+	therm := newFakeThermometer("2811223344556656")
+	therm.parasitic = true
+	bus := newFakeBus(therm.fakeDevice)
+	s := newFakeSensor(bus, therm)
+	s.tConv = 50 * time.Millisecond
+
+	// another user of the bus tries to get it as soon as the conversion starts
+	acquired := make(chan time.Time)
+	therm.onConvert = func() {
+		go func() {
+			bus.lock()
+			defer bus.unlock()
+			acquired <- time.Now()
+		}()
+	}
+
+	c, err := s.StartConversion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsReady() {
+		t.Error("conversion not ready after StartConversion returned")
+	}
+	if c.ReadyAt().Sub(c.StartedAt()) < s.tConv {
+		t.Errorf("returned after %v, expected at least: %v", c.ReadyAt().Sub(c.StartedAt()), s.tConv)
+	}
+	if at := <-acquired; at.Before(c.ReadyAt()) {
+		t.Error("bus was released during the conversion")
+	}
+
+	if temperature, err := c.ReadWhenReady(); err != nil {
+		t.Fatal(err)
+	} else if temperature != 2506 {
+		t.Errorf("got: %d, expected: 2506", temperature)
+	}
+}
@@ -0,0 +1,91 @@
+package digitemp
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Generic 1-Wire device.
+type Device interface {
+	GetROM() *ROM
+	GetFamilyCode() byte
+	GetName() string
+	Close() error
+}
+
+// Creates device instance for the ROM found on the bus.
+type DeviceFactory func(bus *UARTAdapter, rom *ROM) (Device, error)
+
+// Device driver handling one or more family codes.
+type Driver struct {
+	Name        string
+	Description string
+	FamilyCodes []byte
+	New         DeviceFactory
+}
+
+var registry = struct {
+	mx      sync.RWMutex
+	drivers map[byte]*Driver
+}{
+	drivers: make(map[byte]*Driver),
+}
+
+// Register device driver for its family codes.
+// Fails if any of the family codes is already handled by another driver.
+func RegisterDriver(driver *Driver) error {
+	if driver == nil || driver.New == nil {
+		return fmt.Errorf("driver has no factory")
+	}
+	if len(driver.FamilyCodes) == 0 {
+		return fmt.Errorf("driver %s has no family codes", driver.Name)
+	}
+
+	registry.mx.Lock()
+	defer registry.mx.Unlock()
+
+	for _, fc := range driver.FamilyCodes {
+		if d, ok := registry.drivers[fc]; ok {
+			return fmt.Errorf("family 0x%02X is already handled by driver %s", fc, d.Name)
+		}
+	}
+	for _, fc := range driver.FamilyCodes {
+		registry.drivers[fc] = driver
+	}
+	return nil
+}
+
+// Get driver registered for the family code. Returns nil if there is none.
+func LookupDriver(familyCode byte) *Driver {
+	registry.mx.RLock()
+	defer registry.mx.RUnlock()
+
+	return registry.drivers[familyCode]
+}
+
+// Get all registered drivers ordered by their first family code.
+func GetDrivers() []*Driver {
+	registry.mx.RLock()
+	defer registry.mx.RUnlock()
+
+	seen := make(map[*Driver]bool)
+	drivers := make([]*Driver, 0, len(registry.drivers))
+	for _, d := range registry.drivers {
+		if !seen[d] {
+			seen[d] = true
+			drivers = append(drivers, d)
+		}
+	}
+	sort.Slice(drivers, func(i, j int) bool {
+		return drivers[i].FamilyCodes[0] < drivers[j].FamilyCodes[0]
+	})
+	return drivers
+}
+
+// Register driver and panic on failure. To be used in init() of built-in drivers.
+func mustRegisterDriver(driver *Driver) {
+	if err := RegisterDriver(driver); err != nil {
+		panic(err)
+	}
+}
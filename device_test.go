@@ -0,0 +1,20 @@
+package digitemp
+
+import "testing"
+
+func TestRegisterDriver(t *testing.T) {
+	if d := LookupDriver(FamilyDS18B20); d == nil || d.Name != "DS18B20" {
+		t.Errorf("DS18B20 driver is not registered: %v", d)
+	}
+	dup := &Driver{
+		Name:        "Duplicate",
+		FamilyCodes: []byte{FamilyDS18B20},
+		New:         newTemperatureSensorDevice,
+	}
+	if err := RegisterDriver(dup); err == nil {
+		t.Error("duplicate family code registered")
+	}
+	if err := RegisterDriver(&Driver{Name: "NoFactory", FamilyCodes: []byte{0xfe}}); err == nil {
+		t.Error("driver without factory registered")
+	}
+}
@@ -0,0 +1,121 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Device of a family no driver is registered for.
+type GenericDevice struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func NewGenericDevice(bus *UARTAdapter, rom *ROM) *GenericDevice {
+	return &GenericDevice{
+		bus: bus,
+		rom: rom,
+	}
+}
+
+func (d *GenericDevice) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *GenericDevice) GetFamilyCode() byte {
+	return d.rom.Family()
+}
+
+func (d *GenericDevice) GetName() string {
+	if f := d.rom.FamilyInfo(); f != nil {
+		return f.String()
+	}
+	return fmt.Sprintf("Unidentified device (family 0x%02X)", d.GetFamilyCode())
+}
+
+// Get kind of the device. Returns empty string if the family is unknown.
+func (d *GenericDevice) GetCategory() FamilyCategory {
+	if f := d.rom.FamilyInfo(); f != nil {
+		return f.Category
+	}
+	return ""
+}
+
+// Check device is connected to the bus
+func (d *GenericDevice) IsConnected() (bool, error) {
+	return d.bus.IsConnected(d.rom)
+}
+
+func (d *GenericDevice) Close() error {
+	return nil
+}
+
+// Device found on the bus which failed to initialise.
+type DiscoveryFailure struct {
+	ROM    *ROM
+	Driver *Driver // nil if the failure happened before looking for the driver
+	Err    error
+}
+
+func (f *DiscoveryFailure) Error() string {
+	if f.Driver != nil {
+		return fmt.Sprintf("%s (%s): %s", f.ROM, f.Driver.Name, f.Err)
+	}
+	return fmt.Sprintf("%s: %s", f.ROM, f.Err)
+}
+
+func (f *DiscoveryFailure) Unwrap() error {
+	return f.Err
+}
+
+// Result of bus discovery.
+type Discovery struct {
+	Devices []Device            // successfully initialised devices in search order
+	Failed  []*DiscoveryFailure // devices failed to initialise
+}
+
+// Search the bus and create device instances for all found devices using registered drivers.
+// Devices of unknown families are returned as `*GenericDevice`.
+// Returns error only if the search itself failed.
+func Discover(bus *UARTAdapter) (*Discovery, error) {
+	roms, err := bus.GetConnectedROMs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Discovery{
+		Devices: make([]Device, 0, len(roms)),
+		Failed:  make([]*DiscoveryFailure, 0),
+	}
+	for _, rom := range roms {
+		if !rom.IsValid() {
+			result.Failed = append(result.Failed, &DiscoveryFailure{ROM: rom, Err: errors.New("crc error")})
+			continue
+		}
+		driver := LookupDriver(rom.Family())
+		if driver == nil {
+			result.Devices = append(result.Devices, NewGenericDevice(bus, rom))
+			continue
+		}
+		if dev, err := driver.New(bus, rom); err != nil {
+			result.Failed = append(result.Failed, &DiscoveryFailure{ROM: rom, Driver: driver, Err: err})
+		} else {
+			result.Devices = append(result.Devices, dev)
+		}
+	}
+	return result, nil
+}
+
+// Get discovered temperature sensors.
+func (d *Discovery) TemperatureSensors() []*TemperatureSensor {
+	sensors := make([]*TemperatureSensor, 0)
+	for _, dev := range d.Devices {
+		switch s := dev.(type) {
+		case *TemperatureSensor:
+			sensors = append(sensors, s)
+		case *DS28EA00:
+			sensors = append(sensors, s.TemperatureSensor)
+		}
+	}
+	return sensors
+}
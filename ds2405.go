@@ -0,0 +1,151 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const FamilyDS2405 = 0x05
+
+// DS2405 - Addressable Switch.
+//
+// The device has no function commands: every Match ROM toggles its PIO output, after which
+// it sends the sensed PIO level in read time slots. The state is read without toggling with
+// Alarm Search, which the device responds to when PIO is low.
+//
+// There is no way to read the output latch separately, so it's assumed to match the pin level,
+// which is true unless something else pulls the pin low.
+type DS2405 struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2405",
+		Description: "DS2405 - Addressable Switch",
+		FamilyCodes: []byte{FamilyDS2405},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2405(bus, rom)
+		},
+	})
+}
+
+// Create new DS2405 instance.
+func NewDS2405(bus *UARTAdapter, rom *ROM) (*DS2405, error) {
+	if rom == nil || rom.Family() != FamilyDS2405 {
+		return nil, errors.New("DS2405 requires ROM code of family 0x05")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2405{
+		bus: bus,
+		rom: rom,
+	}, nil
+}
+
+func (d *DS2405) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2405) GetFamilyCode() byte {
+	return FamilyDS2405
+}
+
+func (d *DS2405) GetName() string {
+	return "DS2405 - Addressable Switch"
+}
+
+func (d *DS2405) Close() error {
+	return nil
+}
+
+func (d *DS2405) GetChannelCount() int {
+	return 1
+}
+
+func (d *DS2405) ReadChannels() ([]ChannelState, error) {
+	state, err := d.ReadChannel(0)
+	if err != nil {
+		return nil, err
+	}
+	return []ChannelState{state}, nil
+}
+
+func (d *DS2405) ReadChannel(channel int) (ChannelState, error) {
+	if err := checkChannel(channel, 1); err != nil {
+		return ChannelState{}, err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	level, err := d.readLevel()
+	if err != nil {
+		return ChannelState{}, err
+	}
+	return ChannelState{Pin: level, Latch: level}, nil
+}
+
+func (d *DS2405) SetChannel(channel int, latch bool) error {
+	if err := checkChannel(channel, 1); err != nil {
+		return err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	level, err := d.readLevel()
+	if err != nil {
+		return err
+	}
+	if level == latch {
+		return nil
+	}
+	if level, err = d.toggle(); err != nil {
+		return err
+	} else if level != latch {
+		return fmt.Errorf("channel %d not latched", channel)
+	}
+	return nil
+}
+
+func (d *DS2405) ToggleChannel(channel int) (bool, error) {
+	if err := checkChannel(channel, 1); err != nil {
+		return false, err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.toggle()
+}
+
+// Toggle PIO with Match ROM and read its new level.
+func (d *DS2405) toggle() (bool, error) {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return false, err
+	}
+	b, err := d.bus.readByte()
+	if err != nil {
+		return false, err
+	}
+	return b != 0, nil
+}
+
+// Read PIO level with Alarm Search.
+func (d *DS2405) readLevel() (bool, error) {
+	roms, err := d.bus.searchROM(true)
+	if err != nil {
+		return false, err
+	}
+	for _, rom := range roms {
+		if *rom == *d.rom {
+			return false, nil
+		}
+	}
+	return true, nil
+}
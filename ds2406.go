@@ -0,0 +1,330 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const FamilyDS2406 = 0x12
+
+// Channel control byte 1 of CHANNEL ACCESS.
+const (
+	ds2406CRC   = 0b00000001 // CRC16 after every byte
+	ds2406ChA   = 0b00000100 // channel A selected
+	ds2406ChB   = 0b00001000 // channel B selected
+	ds2406ChAB  = ds2406ChA | ds2406ChB
+	ds2406IC    = 0b00010000 // synchronous mode
+	ds2406TOG   = 0b00100000 // toggle read/write after every 8 bits
+	ds2406IM    = 0b01000000 // interleave mode
+	ds2406ALR   = 0b10000000 // reset activity latches
+	ds2406Bytes = 128        // size of EPROM data memory
+)
+
+// Channel info byte returned by CHANNEL ACCESS of DS2406/DS2407.
+//
+//	bit 0, 1 - PIO-A, PIO-B flip-flop, 0 means the output transistor is on
+//	bit 2, 3 - PIO-A, PIO-B sensed level
+//	bit 4, 5 - PIO-A, PIO-B activity latch
+//	bit 6    - number of channels: 0 - one (TSOC package), 1 - two
+//	bit 7    - VCC power present
+type DS2406Info byte
+
+func (i DS2406Info) FlipFlop(channel int) bool {
+	return (i>>channel)&0b1 != 0
+}
+
+func (i DS2406Info) Sensed(channel int) bool {
+	return (i>>(channel+2))&0b1 != 0
+}
+
+func (i DS2406Info) Activity(channel int) bool {
+	return (i>>(channel+4))&0b1 != 0
+}
+
+func (i DS2406Info) ChannelCount() int {
+	if i&0b01000000 != 0 {
+		return 2
+	}
+	return 1
+}
+
+func (i DS2406Info) IsPowered() bool {
+	return i&0b10000000 != 0
+}
+
+// DS2406/DS2407 - Dual Addressable Switch Plus 1Kb Memory.
+//
+// Output flip-flops are kept in status memory byte 7 along with conditional search settings:
+//
+//	bit 0..4 - CSS0..CSS4 conditional search settings
+//	bit 5, 6 - PIO-A, PIO-B flip-flop
+//	bit 7    - VCC power present (read only)
+type DS2406 struct {
+	bus      *UARTAdapter
+	rom      *ROM
+	channels int
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2406",
+		Description: "DS2406/DS2407 - Dual Addressable Switch with 1Kb Memory",
+		FamilyCodes: []byte{FamilyDS2406},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2406(bus, rom)
+		},
+	})
+}
+
+// Create new DS2406/DS2407 instance.
+func NewDS2406(bus *UARTAdapter, rom *ROM) (*DS2406, error) {
+	if rom == nil || rom.Family() != FamilyDS2406 {
+		return nil, errors.New("DS2406 requires ROM code of family 0x12")
+	}
+	d := &DS2406{
+		bus: bus,
+		rom: rom,
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if online, err := d.bus.isConnected(d.rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", d.rom)
+	}
+	info, err := d.channelAccess(ds2406ChA)
+	if err != nil {
+		return nil, err
+	}
+	d.channels = info.ChannelCount()
+	return d, nil
+}
+
+func (d *DS2406) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2406) GetFamilyCode() byte {
+	return FamilyDS2406
+}
+
+func (d *DS2406) GetName() string {
+	return "DS2406/DS2407 - Dual Addressable Switch with 1Kb Memory"
+}
+
+func (d *DS2406) Close() error {
+	return nil
+}
+
+func (d *DS2406) GetChannelCount() int {
+	return d.channels
+}
+
+// Read flip-flops, sensed levels and activity latches.
+func (d *DS2406) ReadInfo() (DS2406Info, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.channelAccess(ds2406ChA)
+}
+
+// Clear activity latches of both channels. Returns channel info read before the reset.
+func (d *DS2406) ResetActivityLatches() (DS2406Info, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.channelAccess(ds2406ALR | ds2406ChA)
+}
+
+// Read 8 bytes of status memory.
+func (d *DS2406) ReadStatus() ([]byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readWithCRC(0xaa, 0, 8)
+}
+
+// Read EPROM data memory from the address to its end.
+func (d *DS2406) ReadMemory(address byte) ([]byte, error) {
+	if address >= ds2406Bytes {
+		return nil, fmt.Errorf("wrong address: 0x%02X", address)
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readWithCRC(0xf0, address, ds2406Bytes)
+}
+
+// Write output flip-flops. False turns output transistor on and pulls the pin low.
+func (d *DS2406) WritePIO(pioA bool, pioB bool) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writeFlipFlops(pioA, pioB)
+}
+
+func (d *DS2406) ReadChannels() ([]ChannelState, error) {
+	info, err := d.ReadInfo()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]ChannelState, d.channels)
+	for ch := range states {
+		states[ch] = ChannelState{Pin: info.Sensed(ch), Latch: info.FlipFlop(ch)}
+	}
+	return states, nil
+}
+
+func (d *DS2406) ReadChannel(channel int) (ChannelState, error) {
+	if err := checkChannel(channel, d.channels); err != nil {
+		return ChannelState{}, err
+	}
+	info, err := d.ReadInfo()
+	if err != nil {
+		return ChannelState{}, err
+	}
+	return ChannelState{Pin: info.Sensed(channel), Latch: info.FlipFlop(channel)}, nil
+}
+
+func (d *DS2406) SetChannel(channel int, latch bool) error {
+	if err := checkChannel(channel, d.channels); err != nil {
+		return err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	info, err := d.channelAccess(ds2406ChA)
+	if err != nil {
+		return err
+	}
+	return d.setFlipFlop(info, channel, latch)
+}
+
+func (d *DS2406) ToggleChannel(channel int) (bool, error) {
+	if err := checkChannel(channel, d.channels); err != nil {
+		return false, err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	info, err := d.channelAccess(ds2406ChA)
+	if err != nil {
+		return false, err
+	}
+	latch := !info.FlipFlop(channel)
+	return latch, d.setFlipFlop(info, channel, latch)
+}
+
+func (d *DS2406) setFlipFlop(info DS2406Info, channel int, latch bool) error {
+	flipFlops := [2]bool{info.FlipFlop(PIOA), info.FlipFlop(PIOB)}
+	flipFlops[channel] = latch
+	if err := d.writeFlipFlops(flipFlops[PIOA], flipFlops[PIOB]); err != nil {
+		return err
+	}
+	if info, err := d.channelAccess(ds2406ChA); err != nil {
+		return err
+	} else if info.FlipFlop(channel) != latch {
+		return fmt.Errorf("channel %d not latched (info: 0x%02X)", channel, byte(info))
+	}
+	return nil
+}
+
+func (d *DS2406) writeFlipFlops(pioA bool, pioB bool) error {
+	status, err := d.readWithCRC(0xaa, 7, 8)
+	if err != nil {
+		return err
+	}
+	data := status[0] & 0b00011111
+	if pioA {
+		data |= 0b00100000
+	}
+	if pioB {
+		data |= 0b01000000
+	}
+	return d.writeStatus(7, data)
+}
+
+//
+// CHANNEL ACCESS [F5h]
+//
+// Sends channel control bytes and reads channel info byte followed by one byte of sampled
+// channel data and CRC16 of the command, control bytes, info and data.
+//
+func (d *DS2406) channelAccess(control byte) (DS2406Info, error) {
+	command := []byte{0xf5, control | ds2406CRC, 0xff}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return 0, err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return 0, err
+	}
+	var data = make([]byte, 4)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return 0, err
+	}
+	if !checkCRC16(append(command, data[0:2]...), data[2:4]) {
+		return 0, errors.New("channel access crc error")
+	}
+	return DS2406Info(data[0]), nil
+}
+
+//
+// READ MEMORY [F0h], READ STATUS [AAh]
+//
+// Reads memory from the address up to the end followed by CRC16 of the command, address and data.
+//
+func (d *DS2406) readWithCRC(command byte, address byte, size int) ([]byte, error) {
+	header := []byte{command, address, 0x00}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if _, err := d.bus.writeBytes(header); err != nil {
+		return nil, err
+	}
+	var data = make([]byte, size-int(address)+2)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return nil, err
+	}
+	n := len(data) - 2
+	if !checkCRC16(append(header, data[0:n]...), data[n:]) {
+		return nil, errors.New("read memory crc error")
+	}
+	return data[0:n], nil
+}
+
+//
+// WRITE STATUS [55h]
+//
+// Only status byte 7 is supported: it's SRAM and doesn't need programming pulse.
+// The device confirms the data with CRC16 and returns the byte read back.
+//
+func (d *DS2406) writeStatus(address byte, data byte) error {
+	if address != 7 {
+		return fmt.Errorf("writing EPROM status byte 0x%02X is not supported", address)
+	}
+	command := []byte{0x55, address, 0x00, data}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return err
+	}
+	var crc [2]byte
+	if _, err := d.bus.readBytes(crc[0:2]); err != nil {
+		return err
+	}
+	if !checkCRC16(command, crc[0:2]) {
+		return errors.New("write status crc error")
+	}
+	if readBack, err := d.bus.readByte(); err != nil {
+		return err
+	} else if readBack&0x7f != data&0x7f {
+		return fmt.Errorf("write status verification failed (wrote: 0x%02X, read: 0x%02X)", data, readBack)
+	}
+	return nil
+}
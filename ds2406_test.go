@@ -0,0 +1,17 @@
+package digitemp
+
+import "testing"
+
+func TestDS2406Info(t *testing.T) {
+	// two channels, PIO-A: flip-flop off, sensed high, activity; PIO-B: flip-flop on, sensed low
+	info := DS2406Info(0b01010101)
+	if info.ChannelCount() != 2 || info.IsPowered() {
+		t.Errorf("channels: %d, powered: %t", info.ChannelCount(), info.IsPowered())
+	}
+	if !info.FlipFlop(PIOA) || !info.Sensed(PIOA) || !info.Activity(PIOA) {
+		t.Errorf("PIO-A: %t, %t, %t", info.FlipFlop(PIOA), info.Sensed(PIOA), info.Activity(PIOA))
+	}
+	if info.FlipFlop(PIOB) || info.Sensed(PIOB) || info.Activity(PIOB) {
+		t.Errorf("PIO-B: %t, %t, %t", info.FlipFlop(PIOB), info.Sensed(PIOB), info.Activity(PIOB))
+	}
+}
@@ -0,0 +1,414 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const FamilyDS2408 = 0x29
+
+// Control/status register of DS2408 (address 8Dh).
+type DS2408Control byte
+
+const (
+	DS2408PLS  DS2408Control = 1 << 0 // conditional search source: 0 - pin states, 1 - activity latches
+	DS2408CT   DS2408Control = 1 << 1 // conditional search term: 0 - OR of selected channels, 1 - AND
+	DS2408ROS  DS2408Control = 1 << 2 // RSTZ pin mode: 0 - reset input, 1 - strobe output
+	DS2408PORL DS2408Control = 1 << 3 // power-on reset latch, cleared by writing 0
+	DS2408VCCP DS2408Control = 1 << 7 // VCC power status (read only)
+)
+
+// Channel states compared by conditional search, selected by PLS bit of control register.
+type DS2408SearchSource byte
+
+const (
+	DS2408SearchPins     DS2408SearchSource = iota // logic state of the pins
+	DS2408SearchActivity                           // activity latches, set when a pin changed since the last reset
+)
+
+// Registers of DS2408 read at once with READ PIO REGISTERS.
+type DS2408Registers struct {
+	PinState       byte // logic state of P7..P0 pins
+	LatchState     byte // output latches, 0 means the output transistor is on
+	ActivityLatch  byte // channels which changed state since the last reset of activity latches
+	SearchMask     byte // channels participating in conditional search
+	SearchPolarity byte // channel state which makes the device respond to conditional search
+	Control        DS2408Control
+}
+
+// DS2408 - 8-Channel Addressable Switch.
+//
+// With conditional search configured, the device responds to Alarm Search when its inputs
+// match the condition, so `bus.GetROMsWithAlarm()` finds boards whose inputs changed.
+type DS2408 struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2408",
+		Description: "DS2408 - 8-Channel Addressable Switch",
+		FamilyCodes: []byte{FamilyDS2408},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2408(bus, rom)
+		},
+	})
+}
+
+// Create new DS2408 instance.
+func NewDS2408(bus *UARTAdapter, rom *ROM) (*DS2408, error) {
+	if rom == nil || rom.Family() != FamilyDS2408 {
+		return nil, errors.New("DS2408 requires ROM code of family 0x29")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2408{
+		bus: bus,
+		rom: rom,
+	}, nil
+}
+
+func (d *DS2408) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2408) GetFamilyCode() byte {
+	return FamilyDS2408
+}
+
+func (d *DS2408) GetName() string {
+	return "DS2408 - 8-Channel Addressable Switch"
+}
+
+func (d *DS2408) Close() error {
+	return nil
+}
+
+func (d *DS2408) GetChannelCount() int {
+	return 8
+}
+
+// Read all PIO registers.
+func (d *DS2408) ReadRegisters() (*DS2408Registers, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readRegisters()
+}
+
+// Read logic state of P7..P0 pins.
+func (d *DS2408) ReadPIO() (byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readPIO()
+}
+
+// Write output latches of P7..P0. Bit 0 turns output transistor on and pulls the pin low.
+// Returns pin state after the write.
+func (d *DS2408) WritePIO(data byte) (byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if pins, err := d.writePIO([]byte{data}); err != nil {
+		return 0, err
+	} else {
+		return pins[0], nil
+	}
+}
+
+// Write sequence of output latch values in one transaction without resetting the bus between them.
+// It's much faster than separate writes, e.g. to drive a parallel bus connected to PIO.
+// Returns pin state after every write.
+func (d *DS2408) WritePIOSequence(data []byte) ([]byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writePIO(data)
+}
+
+// Clear activity latches of all channels.
+func (d *DS2408) ResetActivityLatches() error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.resetActivityLatches()
+}
+
+// Write control/status register. Power-on reset latch is cleared if PORL bit is 0.
+func (d *DS2408) WriteControl(control DS2408Control) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writeRegisters(0x8d, []byte{byte(control &^ DS2408VCCP)})
+}
+
+// Clear power-on reset latch keeping the rest of the control/status register.
+func (d *DS2408) ClearPowerOnReset() error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	return d.writeRegisters(0x8d, []byte{byte(r.Control &^ (DS2408PORL | DS2408VCCP))})
+}
+
+// Configure conditional search. The device responds to Alarm Search if any (or all, if and is true)
+// of the channels selected by mask have the state given by polarity. Source selects pin states or
+// activity latches to be compared. To find boards whose inputs changed, select activity latches with
+// polarity 1 and OR term, and reset the latches after handling the change.
+func (d *DS2408) SetConditionalSearch(mask byte, polarity byte, and bool, source DS2408SearchSource) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	return d.writeRegisters(0x8b, []byte{mask, polarity, byte(conditionalSearchControl(r.Control, and, source))})
+}
+
+// Control register for conditional search keeping RSTZ mode and not clearing power-on reset latch.
+func conditionalSearchControl(current DS2408Control, and bool, source DS2408SearchSource) DS2408Control {
+	control := current&DS2408ROS | DS2408PORL
+	if and {
+		control |= DS2408CT
+	}
+	if source == DS2408SearchActivity {
+		control |= DS2408PLS
+	}
+	return control
+}
+
+// Configure RSTZ pin as strobe output (true) or reset input (false).
+func (d *DS2408) SetStrobeOutput(strobe bool) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	control := r.Control&(DS2408PLS|DS2408CT) | DS2408PORL
+	if strobe {
+		control |= DS2408ROS
+	}
+	return d.writeRegisters(0x8d, []byte{byte(control)})
+}
+
+func (d *DS2408) ReadChannels() ([]ChannelState, error) {
+	r, err := d.ReadRegisters()
+	if err != nil {
+		return nil, err
+	}
+	states := make([]ChannelState, 8)
+	for ch := range states {
+		states[ch] = ChannelState{
+			Pin:   r.PinState&(1<<ch) != 0,
+			Latch: r.LatchState&(1<<ch) != 0,
+		}
+	}
+	return states, nil
+}
+
+func (d *DS2408) ReadChannel(channel int) (ChannelState, error) {
+	if err := checkChannel(channel, 8); err != nil {
+		return ChannelState{}, err
+	}
+	states, err := d.ReadChannels()
+	if err != nil {
+		return ChannelState{}, err
+	}
+	return states[channel], nil
+}
+
+func (d *DS2408) SetChannel(channel int, latch bool) error {
+	if err := checkChannel(channel, 8); err != nil {
+		return err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	return d.setLatch(r.LatchState, channel, latch)
+}
+
+func (d *DS2408) ToggleChannel(channel int) (bool, error) {
+	if err := checkChannel(channel, 8); err != nil {
+		return false, err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return false, err
+	}
+	latch := r.LatchState&(1<<channel) == 0
+	return latch, d.setLatch(r.LatchState, channel, latch)
+}
+
+func (d *DS2408) setLatch(latches byte, channel int, latch bool) error {
+	if latch {
+		latches |= 1 << channel
+	} else {
+		latches &^= 1 << channel
+	}
+	if _, err := d.writePIO([]byte{latches}); err != nil {
+		return err
+	}
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	if (r.LatchState&(1<<channel) != 0) != latch {
+		return fmt.Errorf("channel %d not latched (latches: 0x%02X)", channel, r.LatchState)
+	}
+	return nil
+}
+
+//
+// READ PIO REGISTERS [F0h]
+//
+// Reads registers from 88h up to 8Fh followed by CRC16 of the command, address and data.
+//
+func (d *DS2408) readRegisters() (*DS2408Registers, error) {
+	command := []byte{0xf0, 0x88, 0x00}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return nil, err
+	}
+	var data = make([]byte, 10)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return nil, err
+	}
+	if !checkCRC16(append(command, data[0:8]...), data[8:10]) {
+		return nil, errors.New("read pio registers crc error")
+	}
+	return decodeDS2408Registers(data[0:8]), nil
+}
+
+//
+// CHANNEL ACCESS READ [F5h]
+//
+// The device keeps sampling pins while the master reads; only the first sample is used.
+//
+func (d *DS2408) readPIO() (byte, error) {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0xf5); err != nil {
+		return 0, err
+	}
+	return d.bus.readByte()
+}
+
+//
+// CHANNEL ACCESS WRITE [5Ah]
+//
+// Each data byte is followed by its complement, the device confirms it with AAh
+// and returns pin state. Any number of bytes can be written in one transaction.
+//
+func (d *DS2408) writePIO(data []byte) ([]byte, error) {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if err := d.bus.writeByte(0x5a); err != nil {
+		return nil, err
+	}
+	pins := make([]byte, len(data))
+	for n, b := range data {
+		if err := d.bus.writeConfirmedByte(b); err != nil {
+			return nil, err
+		}
+		if p, err := d.bus.readByte(); err != nil {
+			return nil, err
+		} else {
+			pins[n] = p
+		}
+	}
+	return pins, nil
+}
+
+//
+// RESET ACTIVITY LATCHES [C3h]
+//
+// The device confirms reset with AAh.
+//
+func (d *DS2408) resetActivityLatches() error {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if err := d.bus.writeByte(0xc3); err != nil {
+		return err
+	}
+	if b, err := d.bus.readByte(); err != nil {
+		return err
+	} else if b != 0xaa {
+		return fmt.Errorf("reset activity latches not confirmed (got: 0x%02X)", b)
+	}
+	return nil
+}
+
+//
+// WRITE CONDITIONAL SEARCH REGISTER [CCh]
+//
+// Writes registers from the address (8Bh..8Dh) on. There is no confirmation,
+// so the registers are read back and verified.
+//
+func (d *DS2408) writeRegisters(address byte, data []byte) error {
+	if address < 0x8b || int(address)+len(data) > 0x8e {
+		return fmt.Errorf("write beyond conditional search registers: %d bytes at 0x%02X", len(data), address)
+	}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0xcc, address, 0x00}); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes(data); err != nil {
+		return err
+	}
+
+	r, err := d.readRegisters()
+	if err != nil {
+		return err
+	}
+	readBack := []byte{r.SearchMask, r.SearchPolarity, byte(r.Control)}[address-0x8b:]
+	for n, b := range data {
+		mask := byte(0xff)
+		if address+byte(n) == 0x8d {
+			// power-on reset latch can only be cleared, VCC status is read only
+			mask = byte(DS2408PLS | DS2408CT | DS2408ROS)
+		}
+		if readBack[n]&mask != b&mask {
+			return fmt.Errorf("register verification failed at 0x%02X (wrote: 0x%02X, read: 0x%02X)", address+byte(n), b, readBack[n])
+		}
+	}
+	return nil
+}
+
+// PIO registers 88h..8Fh.
+func decodeDS2408Registers(data []byte) *DS2408Registers {
+	return &DS2408Registers{
+		PinState:       data[0],
+		LatchState:     data[1],
+		ActivityLatch:  data[2],
+		SearchMask:     data[3],
+		SearchPolarity: data[4],
+		Control:        DS2408Control(data[5]),
+	}
+}
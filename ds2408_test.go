@@ -0,0 +1,38 @@
+package digitemp
+
+import "testing"
+
+func TestDecodeDS2408Registers(t *testing.T) {
+	r := decodeDS2408Registers([]byte{0xf0, 0xff, 0x01, 0x0f, 0x00, 0x8b, 0xff, 0xff})
+	expected := DS2408Registers{
+		PinState:       0xf0,
+		LatchState:     0xff,
+		ActivityLatch:  0x01,
+		SearchMask:     0x0f,
+		SearchPolarity: 0x00,
+		Control:        DS2408VCCP | DS2408PORL | DS2408CT | DS2408PLS,
+	}
+	if *r != expected {
+		t.Errorf("got: %+v, expected: %+v", *r, expected)
+	}
+}
+
+func TestConditionalSearchControl(t *testing.T) {
+	var testcases = []struct {
+		current  DS2408Control
+		and      bool
+		source   DS2408SearchSource
+		expected byte
+	}{
+		{0x88, false, DS2408SearchActivity, 0x09}, // activity latches, OR
+		{0x00, false, DS2408SearchPins, 0x08},
+		{0x00, true, DS2408SearchPins, 0x0a},
+		{DS2408ROS | DS2408PLS | DS2408CT, true, DS2408SearchActivity, 0x0f},
+		{DS2408ROS | DS2408CT, false, DS2408SearchPins, 0x0c},
+	}
+	for n, tc := range testcases {
+		if control := conditionalSearchControl(tc.current, tc.and, tc.source); byte(control) != tc.expected {
+			t.Errorf("(%d, got: 0x%02X, expected: 0x%02X)", n, byte(control), tc.expected)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+)
+
+const FamilyDS2413 = 0x3a
+
+// DS2413 - Dual Channel Addressable Switch.
+type DS2413 struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2413",
+		Description: "DS2413 - Dual Channel Addressable Switch",
+		FamilyCodes: []byte{FamilyDS2413},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2413(bus, rom)
+		},
+	})
+}
+
+// Create new DS2413 instance.
+func NewDS2413(bus *UARTAdapter, rom *ROM) (*DS2413, error) {
+	if rom == nil || rom.Family() != FamilyDS2413 {
+		return nil, errors.New("DS2413 requires ROM code of family 0x3A")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2413{
+		bus: bus,
+		rom: rom,
+	}, nil
+}
+
+func (d *DS2413) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2413) GetFamilyCode() byte {
+	return FamilyDS2413
+}
+
+func (d *DS2413) GetName() string {
+	return "DS2413 - Dual Channel Addressable Switch"
+}
+
+func (d *DS2413) Close() error {
+	return nil
+}
+
+func (d *DS2413) GetChannelCount() int {
+	return 2
+}
+
+// Read state of PIOA and PIOB.
+func (d *DS2413) ReadPIO() (PIOStatus, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readPIO()
+}
+
+// Write output latches of PIOA and PIOB. False turns output transistor on and pulls the pin low.
+// Returns PIO status after the write.
+func (d *DS2413) WritePIO(pioA bool, pioB bool) (PIOStatus, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writePIO(pioA, pioB)
+}
+
+func (d *DS2413) ReadChannels() ([]ChannelState, error) {
+	status, err := d.ReadPIO()
+	if err != nil {
+		return nil, err
+	}
+	return []ChannelState{
+		{Pin: status.Pin(PIOA), Latch: status.Latch(PIOA)},
+		{Pin: status.Pin(PIOB), Latch: status.Latch(PIOB)},
+	}, nil
+}
+
+func (d *DS2413) ReadChannel(channel int) (ChannelState, error) {
+	if err := checkChannel(channel, 2); err != nil {
+		return ChannelState{}, err
+	}
+	status, err := d.ReadPIO()
+	if err != nil {
+		return ChannelState{}, err
+	}
+	return ChannelState{Pin: status.Pin(channel), Latch: status.Latch(channel)}, nil
+}
+
+func (d *DS2413) SetChannel(channel int, latch bool) error {
+	if err := checkChannel(channel, 2); err != nil {
+		return err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	status, err := d.readPIO()
+	if err != nil {
+		return err
+	}
+	return d.setLatch(status, channel, latch)
+}
+
+func (d *DS2413) ToggleChannel(channel int) (bool, error) {
+	if err := checkChannel(channel, 2); err != nil {
+		return false, err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	status, err := d.readPIO()
+	if err != nil {
+		return false, err
+	}
+	latch := !status.Latch(channel)
+	return latch, d.setLatch(status, channel, latch)
+}
+
+func (d *DS2413) setLatch(status PIOStatus, channel int, latch bool) error {
+	latches := [2]bool{status.Latch(PIOA), status.Latch(PIOB)}
+	latches[channel] = latch
+	status, err := d.writePIO(latches[PIOA], latches[PIOB])
+	if err != nil {
+		return err
+	}
+	if status.Latch(channel) != latch {
+		return fmt.Errorf("channel %d not latched (status: 0x%X)", channel, byte(status))
+	}
+	return nil
+}
+
+//
+// PIO ACCESS READ [F5h]
+//
+// Returns status byte with pin and latch states in the lower nibble and their complement in the upper one.
+//
+func (d *DS2413) readPIO() (PIOStatus, error) {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0xf5); err != nil {
+		return 0, err
+	}
+	return d.bus.readPIOStatus()
+}
+
+//
+// PIO ACCESS WRITE [5Ah]
+//
+// Data byte is followed by its complement, the device confirms it with AAh and returns new PIO status.
+// Unused upper bits of the data are written as 1.
+//
+func (d *DS2413) writePIO(pioA bool, pioB bool) (PIOStatus, error) {
+	data := encodePIOLatches(pioA, pioB)
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0x5a); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeConfirmedByte(data); err != nil {
+		return 0, err
+	}
+	return d.bus.readPIOStatus()
+}
@@ -0,0 +1,130 @@
+package digitemp
+
+import "testing"
+
+// Fake DS2413 with pins following output latches.
+type fakeDS2413 struct {
+	*fakeDevice
+	latches   byte // bit 0 - PIOA, bit 1 - PIOB
+	stuck     byte // latches which ignore writes
+	noConfirm bool
+}
+
+func newFakeDS2413() *fakeDS2413 {
+	r, _ := NewROMFromString("3A112233445566D7")
+	d := &fakeDS2413{fakeDevice: &fakeDevice{rom: *r}, latches: 0b11}
+	d.onFunction = d.function
+	return d
+}
+
+func (f *fakeDS2413) status() byte {
+	var s byte
+	for ch := 0; ch < 2; ch++ {
+		bit := (f.latches >> ch) & 0b1
+		s |= (bit | bit<<1) << (ch * 2)
+	}
+	return ^s<<4 | s
+}
+
+func (f *fakeDS2413) function(d *fakeDevice, command byte) {
+	switch command {
+	case 0xf5:
+		d.send(f.status())
+	case 0x5a:
+		d.expect(2, func(data []byte) {
+			if data[1] != ^data[0] {
+				return
+			}
+			f.latches = f.latches&f.stuck | data[0]&0b11&^f.stuck
+			if f.noConfirm {
+				d.send(0x00)
+				return
+			}
+			d.send(0xaa, f.status())
+		})
+	}
+}
+
+func TestEncodePIOLatches(t *testing.T) {
+	var testcases = []struct {
+		pioA, pioB bool
+		data       byte
+	}{
+		{false, false, 0xfc},
+		{true, false, 0xfd},
+		{false, true, 0xfe},
+		{true, true, 0xff},
+	}
+	for _, tc := range testcases {
+		if data := encodePIOLatches(tc.pioA, tc.pioB); data != tc.data {
+			t.Errorf("(%t, %t) got: 0x%02X, expected: 0x%02X", tc.pioA, tc.pioB, data, tc.data)
+		}
+	}
+}
+
+func TestDecodePIOStatus(t *testing.T) {
+	var testcases = []struct {
+		data   byte
+		status PIOStatus
+		valid  bool
+	}{
+		{0xf0, 0x0, true},
+		{0x0f, 0xf, true},
+		{0x5a, 0xa, true}, // pins low, latches off
+		{0x78, 0x8, true},
+		{0xff, 0, false},
+		{0x00, 0, false},
+		{0x5b, 0, false}, // one bit flipped
+	}
+	for _, tc := range testcases {
+		status, err := decodePIOStatus(tc.data)
+		if (err == nil) != tc.valid || status != tc.status {
+			t.Errorf("0x%02X got: 0x%X, %v, expected: 0x%X", tc.data, byte(status), err, byte(tc.status))
+		}
+	}
+}
+
+func TestDS2413_Channels(t *testing.T) {
+	device := newFakeDS2413()
+	bus := newFakeBus(device.fakeDevice)
+	rom := device.rom
+	sw, err := NewDS2413(bus, &rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sw.SetChannel(PIOB, false); err != nil {
+		t.Fatal(err)
+	}
+	if device.latches != 0b01 {
+		t.Errorf("latches: %02b", device.latches)
+	}
+	if latch, err := sw.ToggleChannel(PIOA); err != nil || latch {
+		t.Errorf("toggle: %t, %v", latch, err)
+	}
+	states, err := sw.ReadChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ch, state := range states {
+		if state.Pin || state.Latch {
+			t.Errorf("channel %d: %+v", ch, state)
+		}
+	}
+
+	// latch doesn't follow the write
+	device.stuck = 0b01
+	if err := sw.SetChannel(PIOA, true); err == nil {
+		t.Error("stuck latch not detected")
+	}
+
+	// write not confirmed with AAh
+	device.stuck, device.noConfirm = 0, true
+	if err := sw.SetChannel(PIOA, true); err == nil {
+		t.Error("missing confirmation not detected")
+	}
+
+	if err := sw.SetChannel(2, true); err == nil {
+		t.Error("wrong channel accepted")
+	}
+}
@@ -0,0 +1,281 @@
+package digitemp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const FamilyDS2423 = 0x1d
+
+// Counters of DS2423 and memory pages they are tied to.
+const (
+	DS2423CounterA = 0 // counts on input A, page 14 (01C0h)
+	DS2423CounterB = 1 // counts on input B, page 15 (01E0h)
+)
+
+const (
+	ds2423PageSize = 32
+	ds2423Pages    = 16
+)
+
+// DS2423 - 4Kb 1-Wire RAM with Counter.
+//
+// Memory has 16 pages of 32 bytes. Writing to pages 12..15 increments counters
+// of these pages, only counters of pages 14 and 15 are connected to inputs A and B.
+type DS2423 struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2423",
+		Description: "DS2423 - 4Kb RAM with Counter",
+		FamilyCodes: []byte{FamilyDS2423},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2423(bus, rom)
+		},
+	})
+}
+
+// Create new DS2423 instance.
+func NewDS2423(bus *UARTAdapter, rom *ROM) (*DS2423, error) {
+	if rom == nil || rom.Family() != FamilyDS2423 {
+		return nil, errors.New("DS2423 requires ROM code of family 0x1D")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2423{
+		bus: bus,
+		rom: rom,
+	}, nil
+}
+
+func (d *DS2423) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2423) GetFamilyCode() byte {
+	return FamilyDS2423
+}
+
+func (d *DS2423) GetName() string {
+	return "DS2423 - 4Kb RAM with Counter"
+}
+
+func (d *DS2423) Close() error {
+	return nil
+}
+
+// Read counter A or B.
+func (d *DS2423) ReadCounter(counter int) (uint32, error) {
+	if counter != DS2423CounterA && counter != DS2423CounterB {
+		return 0, fmt.Errorf("wrong counter: %d", counter)
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	_, value, err := d.readPageCounter(byte(14 + counter))
+	return value, err
+}
+
+// Read both counters.
+func (d *DS2423) ReadCounters() ([2]uint32, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	var values [2]uint32
+	for counter := range values {
+		if _, value, err := d.readPageCounter(byte(14 + counter)); err != nil {
+			return values, err
+		} else {
+			values[counter] = value
+		}
+	}
+	return values, nil
+}
+
+// Read 32 bytes of memory page 0..15.
+func (d *DS2423) ReadPage(page byte) ([]byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	data, _, err := d.readPageCounter(page)
+	return data, err
+}
+
+// Write data to memory page 0..15 starting at the offset through the scratchpad.
+func (d *DS2423) WritePage(page byte, offset byte, data []byte) error {
+	if page >= ds2423Pages {
+		return fmt.Errorf("wrong page number: %d", page)
+	}
+	if len(data) == 0 || int(offset)+len(data) > ds2423PageSize {
+		return fmt.Errorf("write beyond page: %d bytes at offset %d", len(data), offset)
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	address := uint16(page)*ds2423PageSize + uint16(offset)
+	if err := d.writeScratchpad(address, data); err != nil {
+		return err
+	}
+	es, err := d.verifyScratchpad(address, data)
+	if err != nil {
+		return err
+	}
+	return d.copyScratchpad(address, es)
+}
+
+//
+// READ MEMORY + COUNTER [A5h]
+//
+// Reads the page followed by its 32-bit counter, 4 zero bytes and CRC16 of the command,
+// address, data, counter and zeros. Pages without counter return FFFFFFFFh.
+//
+func (d *DS2423) readPageCounter(page byte) ([]byte, uint32, error) {
+	if page >= ds2423Pages {
+		return nil, 0, fmt.Errorf("wrong page number: %d", page)
+	}
+	address := uint16(page) * ds2423PageSize
+	command := []byte{0xa5, byte(address), byte(address >> 8)}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, 0, err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return nil, 0, err
+	}
+	var data = make([]byte, ds2423PageSize+10)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return nil, 0, err
+	}
+	if !checkCRC16(append(command, data[0:ds2423PageSize+8]...), data[ds2423PageSize+8:]) {
+		return nil, 0, errors.New("read memory crc error")
+	}
+	counter := binary.LittleEndian.Uint32(data[ds2423PageSize : ds2423PageSize+4])
+	return data[0:ds2423PageSize], counter, nil
+}
+
+//
+// WRITE SCRATCHPAD [0Fh]
+//
+func (d *DS2423) writeScratchpad(address uint16, data []byte) error {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0x0f, byte(address), byte(address >> 8)}); err != nil {
+		return err
+	}
+	_, err := d.bus.writeBytes(data)
+	return err
+}
+
+//
+// READ SCRATCHPAD [AAh]
+//
+// Returns target address, ending offset/data status byte (E/S) and data from the offset on.
+// Returns E/S byte needed to copy the scratchpad.
+//
+func (d *DS2423) verifyScratchpad(address uint16, data []byte) (byte, error) {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0xaa); err != nil {
+		return 0, err
+	}
+	var readBack = make([]byte, 3+len(data))
+	if _, err := d.bus.readBytes(readBack); err != nil {
+		return 0, err
+	}
+	es := readBack[2]
+	if binary.LittleEndian.Uint16(readBack[0:2]) != address {
+		return 0, fmt.Errorf("scratchpad target address mismatch: 0x%04X", binary.LittleEndian.Uint16(readBack[0:2]))
+	}
+	// partial flag or wrong ending offset
+	if es&0b00100000 != 0 || es&0x1f != byte(int(address%ds2423PageSize)+len(data)-1) {
+		return 0, fmt.Errorf("scratchpad incomplete (E/S: 0x%02X)", es)
+	}
+	if !bytes.Equal(readBack[3:], data) {
+		return 0, fmt.Errorf("scratchpad verification failed (wrote: % X, read: % X)", data, readBack[3:])
+	}
+	return es, nil
+}
+
+//
+// COPY SCRATCHPAD [5Ah]
+//
+// Authorization pattern is the target address and E/S byte. The device confirms the copy with AAh.
+//
+func (d *DS2423) copyScratchpad(address uint16, es byte) error {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0x5a, byte(address), byte(address >> 8), es}); err != nil {
+		return err
+	}
+	if b, err := d.bus.readByte(); err != nil {
+		return err
+	} else if b != 0xaa {
+		return fmt.Errorf("copy scratchpad not confirmed (got: 0x%02X)", b)
+	}
+	return nil
+}
+
+// Calculates pulse rate from successive counter samples.
+//
+// Counter decreasing between samples is either 32-bit wraparound or the counter was reset,
+// e.g. the device was replaced or lost its backup battery. It's considered a wraparound when the
+// resulting rate doesn't exceed MaxRate (or, when MaxRate is 0, less than half of the counter
+// range was counted), otherwise it's a reset and calculation starts over from the new sample.
+type CounterRate struct {
+	MaxRate float64 // highest plausible rate in pulses per second, 0 if unknown
+	Resets  int     // number of detected counter resets
+
+	value uint32
+	time  time.Time
+	valid bool
+}
+
+// Create rate calculator with the highest plausible rate in pulses per second, 0 if unknown.
+func NewCounterRate(maxRate float64) *CounterRate {
+	return &CounterRate{MaxRate: maxRate}
+}
+
+// Add counter sample taken at the time. Returns rate in pulses per second since the previous sample
+// and false if there is no valid previous sample.
+func (r *CounterRate) Update(value uint32, at time.Time) (float64, bool) {
+	previous, previousTime, valid := r.value, r.time, r.valid
+	r.value, r.time, r.valid = value, at, true
+	if !valid {
+		return 0, false
+	}
+	elapsed := at.Sub(previousTime).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	// unsigned subtraction takes care of wraparound
+	delta := value - previous
+	if value < previous {
+		wrapped := r.MaxRate == 0 && delta < math.MaxUint32/2 ||
+			r.MaxRate > 0 && float64(delta)/elapsed <= r.MaxRate
+		if !wrapped {
+			r.Resets++
+			return 0, false
+		}
+	}
+	return float64(delta) / elapsed, true
+}
+
+// Forget the previous sample.
+func (r *CounterRate) Reset() {
+	r.valid = false
+}
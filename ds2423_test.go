@@ -0,0 +1,47 @@
+package digitemp
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCounterRate(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewCounterRate(1000)
+
+	if _, ok := r.Update(100, start); ok {
+		t.Error("rate without previous sample")
+	}
+	if rate, ok := r.Update(300, start.Add(10*time.Second)); !ok || rate != 20 {
+		t.Errorf("got: %f, %t", rate, ok)
+	}
+
+	// wraparound
+	r.Update(math.MaxUint32-49, start.Add(20*time.Second))
+	if rate, ok := r.Update(50, start.Add(30*time.Second)); !ok || rate != 10 {
+		t.Errorf("wraparound: %f, %t", rate, ok)
+	}
+
+	// reset
+	if _, ok := r.Update(5, start.Add(40*time.Second)); ok || r.Resets != 1 {
+		t.Errorf("reset not detected: %d", r.Resets)
+	}
+	if rate, ok := r.Update(25, start.Add(50*time.Second)); !ok || rate != 2 {
+		t.Errorf("after reset: %f, %t", rate, ok)
+	}
+}
+
+func TestCounterRateUnknownMax(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := NewCounterRate(0)
+
+	r.Update(math.MaxUint32, start)
+	if rate, ok := r.Update(9, start.Add(time.Second)); !ok || rate != 10 {
+		t.Errorf("wraparound: %f, %t", rate, ok)
+	}
+	r.Update(1000000, start.Add(2*time.Second))
+	if _, ok := r.Update(0, start.Add(3*time.Second)); ok {
+		t.Error("reset not detected")
+	}
+}
@@ -0,0 +1,370 @@
+package digitemp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const FamilyDS2438 = 0x26
+
+// Status/configuration register of DS2438 (page 0, byte 0).
+type DS2438Config byte
+
+const (
+	DS2438IAD DS2438Config = 1 << 0 // current A/D control, enables current measurement and ICA
+	DS2438CA  DS2438Config = 1 << 1 // current accumulator configuration
+	DS2438EE  DS2438Config = 1 << 2 // current accumulator shadow selector
+	DS2438AD  DS2438Config = 1 << 3 // voltage A/D input select: 1 - VDD, 0 - VAD
+	DS2438TB  DS2438Config = 1 << 4 // temperature busy flag (read only)
+	DS2438NVB DS2438Config = 1 << 5 // non-volatile memory busy flag (read only)
+	DS2438ADB DS2438Config = 1 << 6 // A/D converter busy flag (read only)
+)
+
+// Voltage A/D converter input.
+type VoltageSource byte
+
+const (
+	VoltageVAD VoltageSource = iota // general purpose A/D input
+	VoltageVDD                      // supply voltage
+)
+
+// DS2438 - Smart Battery Monitor.
+//
+// Besides batteries, it's used by most 1-Wire humidity and multi-sensor boards:
+// sensors are wired to VAD input and measured against VDD.
+type DS2438 struct {
+	bus *UARTAdapter
+	rom *ROM
+	tRW time.Duration // eeprom write time
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2438",
+		Description: "DS2438 - Smart Battery Monitor",
+		FamilyCodes: []byte{FamilyDS2438},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2438(bus, rom)
+		},
+	})
+}
+
+// Create new DS2438 instance.
+func NewDS2438(bus *UARTAdapter, rom *ROM) (*DS2438, error) {
+	if rom == nil || rom.Family() != FamilyDS2438 {
+		return nil, errors.New("DS2438 requires ROM code of family 0x26")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2438{
+		bus: bus,
+		rom: rom,
+		tRW: 10 * time.Millisecond,
+	}, nil
+}
+
+func (d *DS2438) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2438) GetFamilyCode() byte {
+	return FamilyDS2438
+}
+
+func (d *DS2438) GetName() string {
+	return "DS2438 - Smart Battery Monitor"
+}
+
+func (d *DS2438) Close() error {
+	return nil
+}
+
+// Measure temperature.
+func (d *DS2438) GetTemperature() (Temperature, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if err := d.convert(0x44); err != nil {
+		return 0, err
+	}
+	if page, err := d.readPage(0); err != nil {
+		return 0, err
+	} else {
+		return decodeDS2438Temperature(page), nil
+	}
+}
+
+// Measure voltage in V on the input.
+func (d *DS2438) GetVoltage(source VoltageSource) (float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.getVoltage(source)
+}
+
+// Read current in A flowing through the sense resistor with resistance in Ohm.
+// Current A/D converter shall be enabled with IAD bit in configuration.
+func (d *DS2438) GetCurrent(rSense float64) (float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if page, err := d.readPage(0); err != nil {
+		return 0, err
+	} else {
+		return decodeDS2438Current(page, rSense), nil
+	}
+}
+
+// Read status/configuration register.
+func (d *DS2438) ReadConfig() (DS2438Config, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if page, err := d.readPage(0); err != nil {
+		return 0, err
+	} else {
+		return DS2438Config(page[0]), nil
+	}
+}
+
+// Write status/configuration register.
+func (d *DS2438) WriteConfig(config DS2438Config) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writeConfig(config)
+}
+
+// Read 8 bytes of memory page 0..7.
+func (d *DS2438) ReadPage(page byte) ([]byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readPage(page)
+}
+
+// Write up to 8 bytes to memory page 0..7 and verify them.
+func (d *DS2438) WritePage(page byte, data []byte) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writePage(page, data)
+}
+
+func (d *DS2438) getVoltage(source VoltageSource) (float64, error) {
+	page, err := d.readPage(0)
+	if err != nil {
+		return 0, err
+	}
+	config := DS2438Config(page[0])
+	if wantVDD := source == VoltageVDD; wantVDD != (config&DS2438AD != 0) {
+		config ^= DS2438AD
+		if err := d.writeConfig(config); err != nil {
+			return 0, err
+		}
+	}
+	if err := d.convert(0xb4); err != nil {
+		return 0, err
+	}
+	if page, err := d.readPage(0); err != nil {
+		return 0, err
+	} else {
+		return decodeDS2438Voltage(page), nil
+	}
+}
+
+func (d *DS2438) writeConfig(config DS2438Config) error {
+	// busy flags are read only
+	config &^= DS2438TB | DS2438NVB | DS2438ADB
+	return d.writePage(0, []byte{byte(config)})
+}
+
+// CONVERT T [44h], CONVERT V [B4h]
+// Initiate conversion and wait for it to finish.
+func (d *DS2438) convert(command byte) error {
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if err := d.bus.writeByte(command); err != nil {
+		return err
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+// RECALL MEMORY [B8h] and READ SCRATCHPAD [BEh]
+// Copy memory page to scratchpad and read it.
+func (d *DS2438) readPage(page byte) ([]byte, error) {
+	if page > 7 {
+		return nil, fmt.Errorf("wrong page number: %d", page)
+	}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if _, err := d.bus.writeBytes([]byte{0xb8, page}); err != nil {
+		return nil, err
+	}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if _, err := d.bus.writeBytes([]byte{0xbe, page}); err != nil {
+		return nil, err
+	}
+	var data = make([]byte, 9)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return nil, err
+	}
+	if crc8(data[0:8]) != data[8] {
+		return nil, errors.New("scratchpad crc error")
+	}
+	return data[0:8], nil
+}
+
+// WRITE SCRATCHPAD [4Eh] and COPY SCRATCHPAD [48h]
+// Write data to scratchpad, verify it and copy to memory page.
+func (d *DS2438) writePage(page byte, data []byte) error {
+	if page > 7 {
+		return fmt.Errorf("wrong page number: %d", page)
+	}
+	if len(data) == 0 || len(data) > 8 {
+		return fmt.Errorf("wrong data length: %d", len(data))
+	}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0x4e, page}); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes(data); err != nil {
+		return err
+	}
+
+	// verify scratchpad before copying
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0xbe, page}); err != nil {
+		return err
+	}
+	var readBack = make([]byte, 9)
+	if _, err := d.bus.readBytes(readBack); err != nil {
+		return err
+	}
+	if crc8(readBack[0:8]) != readBack[8] {
+		return errors.New("scratchpad crc error")
+	}
+	// page 0 has read-only registers, so only the configuration byte without busy flags is checked there
+	verified := bytes.Equal(readBack[0:len(data)], data)
+	if page == 0 {
+		mask := ^byte(DS2438TB | DS2438NVB | DS2438ADB)
+		verified = readBack[0]&mask == data[0]&mask
+	}
+	if !verified {
+		return fmt.Errorf("scratchpad verification failed (wrote: % X, read: % X)", data, readBack[0:len(data)])
+	}
+
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes([]byte{0x48, page}); err != nil {
+		return err
+	}
+	time.Sleep(d.tRW)
+	return nil
+}
+
+// Temperature: 13 bits, 0.03125ºC.
+func decodeDS2438Temperature(page []byte) Temperature {
+	raw := int(int16(binary.LittleEndian.Uint16(page[1:3])) >> 3)
+	return Temperature(raw * temperatureScale / 32)
+}
+
+// Voltage: 10 bits, 10mV.
+func decodeDS2438Voltage(page []byte) float64 {
+	return float64(binary.LittleEndian.Uint16(page[3:5])&0x3ff) * 0.01
+}
+
+// Current: 10 bits with sign, 0.2441mV over sense resistor.
+func decodeDS2438Current(page []byte, rSense float64) float64 {
+	return float64(int16(binary.LittleEndian.Uint16(page[5:7]))) / (4096 * rSense)
+}
+
+// Calculates relative humidity in % from sensor's output voltage, supply voltage and temperature.
+type HumiditySensor func(vOut float64, vSupply float64, t Temperature) float64
+
+// Honeywell HIH-4000 series humidity sensor.
+func HIH4000(vOut float64, vSupply float64, t Temperature) float64 {
+	rh := (vOut/vSupply - 0.16) / 0.0062
+	return rh / (1.0546 - 0.00216*t.Celsius())
+}
+
+// Honeywell HIH-5030/5031 humidity sensor.
+func HIH5030(vOut float64, vSupply float64, t Temperature) float64 {
+	rh := (vOut/vSupply - 0.1515) / 0.00636
+	return rh / (1.0546 - 0.00216*t.Celsius())
+}
+
+// Measure relative humidity in % with humidity sensor wired to VAD and powered from VDD.
+// The reading is compensated with the temperature measured by DS2438.
+func (d *DS2438) GetHumidity(sensor HumiditySensor) (float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if err := d.convert(0x44); err != nil {
+		return 0, err
+	}
+	page, err := d.readPage(0)
+	if err != nil {
+		return 0, err
+	}
+	t := decodeDS2438Temperature(page)
+
+	vdd, err := d.getVoltage(VoltageVDD)
+	if err != nil {
+		return 0, err
+	}
+	vad, err := d.getVoltage(VoltageVAD)
+	if err != nil {
+		return 0, err
+	}
+	if vdd == 0 {
+		return 0, errors.New("no supply voltage")
+	}
+	return sensor(vad, vdd, t), nil
+}
+
+// Sensor with linear output wired to VAD, e.g. light or pressure sensor.
+// Value = VAD * Scale + Offset, or (VAD / VDD) * Scale + Offset if Ratiometric is true.
+type LinearSensor struct {
+	Scale       float64
+	Offset      float64
+	Ratiometric bool // output is proportional to supply voltage
+}
+
+// Measure VAD (and VDD if the sensor is ratiometric) and convert it to sensor's value.
+func (d *DS2438) GetLinearSensor(sensor LinearSensor) (float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	vad, err := d.getVoltage(VoltageVAD)
+	if err != nil {
+		return 0, err
+	}
+	if !sensor.Ratiometric {
+		return vad*sensor.Scale + sensor.Offset, nil
+	}
+	vdd, err := d.getVoltage(VoltageVDD)
+	if err != nil {
+		return 0, err
+	}
+	if vdd == 0 {
+		return 0, errors.New("no supply voltage")
+	}
+	return vad/vdd*sensor.Scale + sensor.Offset, nil
+}
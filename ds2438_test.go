@@ -0,0 +1,41 @@
+package digitemp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDS2438_decode(t *testing.T) {
+	// 25.0625ºC, 4.99V, 100 counts of current
+	page := []byte{0x08, 0x10, 0x19, 0xf3, 0x01, 0x64, 0x00, 0x00}
+	if temp := decodeDS2438Temperature(page); temp != 250625 {
+		t.Errorf("temperature: %s", temp)
+	}
+	if v := decodeDS2438Voltage(page); math.Abs(v-4.99) > 1e-9 {
+		t.Errorf("voltage: %v", v)
+	}
+	if i := decodeDS2438Current(page, 0.025); math.Abs(i-100/(4096*0.025)) > 1e-9 {
+		t.Errorf("current: %v", i)
+	}
+
+	// -10.03125ºC, negative current
+	page = []byte{0x00, 0xf8, 0xf5, 0x00, 0x00, 0x9c, 0xff, 0x00}
+	if temp := decodeDS2438Temperature(page); temp != -100312 {
+		t.Errorf("temperature: %s", temp)
+	}
+	if i := decodeDS2438Current(page, 1); i >= 0 {
+		t.Errorf("current: %v", i)
+	}
+}
+
+func TestHumiditySensors(t *testing.T) {
+	// 50% RH at 25ºC: Vout/Vs = 0.16 + 0.0062*50*(1.0546 - 0.00216*25)
+	ratio := 0.16 + 0.0062*50*(1.0546-0.00216*25)
+	if rh := HIH4000(ratio*5, 5, 250000); math.Abs(rh-50) > 1e-9 {
+		t.Errorf("HIH-4000: %v", rh)
+	}
+	ratio = 0.1515 + 0.00636*50*(1.0546-0.00216*25)
+	if rh := HIH5030(ratio*3.3, 3.3, 250000); math.Abs(rh-50) > 1e-9 {
+		t.Errorf("HIH-5030: %v", rh)
+	}
+}
@@ -0,0 +1,372 @@
+package digitemp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const FamilyDS2450 = 0x20
+
+// Memory pages of DS2450.
+const (
+	DS2450PageConversion  = 0 // conversion results
+	DS2450PageControl     = 1 // control/status
+	DS2450PageAlarm       = 2 // alarm thresholds
+	DS2450PageCalibration = 3 // factory calibration and VCC control byte
+)
+
+// Preset of conversion result register before conversion.
+type ADCPreset byte
+
+const (
+	PresetNone  ADCPreset = 0b00
+	PresetZeros ADCPreset = 0b01
+	PresetOnes  ADCPreset = 0b10
+)
+
+// Configuration of DS2450 channel.
+type ADCChannelConfig struct {
+	Resolution      byte    // 1..16 bits
+	InputRange      float64 // 2.56 or 5.12 V
+	OutputEnable    bool    // channel is used as output
+	OutputControl   bool    // output transistor off if true, on if false
+	AlarmLowEnable  bool
+	AlarmHighEnable bool
+	AlarmLow        byte // low alarm threshold compared with MSB of the result
+	AlarmHigh       byte // high alarm threshold compared with MSB of the result
+}
+
+// Alarm flags of DS2450 channel.
+type ADCAlarm struct {
+	Low  bool
+	High bool
+}
+
+// DS2450 - 1-Wire Quad A/D Converter.
+//
+// After conversion channels with result beyond enabled alarm thresholds respond to Alarm Search,
+// so `bus.GetROMsWithAlarm()` finds them.
+type DS2450 struct {
+	bus *UARTAdapter
+	rom *ROM
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS2450",
+		Description: "DS2450 - Quad A/D Converter",
+		FamilyCodes: []byte{FamilyDS2450},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS2450(bus, rom)
+		},
+	})
+}
+
+// Create new DS2450 instance.
+func NewDS2450(bus *UARTAdapter, rom *ROM) (*DS2450, error) {
+	if rom == nil || rom.Family() != FamilyDS2450 {
+		return nil, errors.New("DS2450 requires ROM code of family 0x20")
+	}
+	if online, err := bus.IsConnected(rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", rom)
+	}
+	return &DS2450{
+		bus: bus,
+		rom: rom,
+	}, nil
+}
+
+func (d *DS2450) GetROM() *ROM {
+	return d.rom
+}
+
+func (d *DS2450) GetFamilyCode() byte {
+	return FamilyDS2450
+}
+
+func (d *DS2450) GetName() string {
+	return "DS2450 - Quad A/D Converter"
+}
+
+func (d *DS2450) Close() error {
+	return nil
+}
+
+// Read 8 bytes of memory page 0..3.
+func (d *DS2450) ReadPage(page byte) ([]byte, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readPage(page)
+}
+
+// Write data to memory starting at the address.
+func (d *DS2450) WriteMemory(address uint16, data []byte) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.writeMemory(address, data)
+}
+
+// Tell the device it's powered from VCC, so conversion doesn't rely on parasite power.
+func (d *DS2450) SetVCCPowered(powered bool) error {
+	var data byte = 0x00
+	if powered {
+		data = 0x40
+	}
+	return d.WriteMemory(DS2450PageCalibration*8+4, []byte{data})
+}
+
+// Read configuration of channel 0..3 (A..D).
+func (d *DS2450) ReadChannelConfig(channel int) (*ADCChannelConfig, error) {
+	if channel < 0 || channel > 3 {
+		return nil, fmt.Errorf("wrong channel: %d", channel)
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	control, err := d.readPage(DS2450PageControl)
+	if err != nil {
+		return nil, err
+	}
+	alarm, err := d.readPage(DS2450PageAlarm)
+	if err != nil {
+		return nil, err
+	}
+	return decodeADCChannelConfig(control[channel*2:channel*2+2], alarm[channel*2:channel*2+2]), nil
+}
+
+// Write configuration of channel 0..3 (A..D). Alarm flags are cleared.
+func (d *DS2450) WriteChannelConfig(channel int, config *ADCChannelConfig) error {
+	if channel < 0 || channel > 3 {
+		return fmt.Errorf("wrong channel: %d", channel)
+	}
+	control, alarm, err := encodeADCChannelConfig(config)
+	if err != nil {
+		return err
+	}
+
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if err := d.writeMemory(uint16(DS2450PageControl*8+channel*2), control); err != nil {
+		return err
+	}
+	return d.writeMemory(uint16(DS2450PageAlarm*8+channel*2), alarm)
+}
+
+// Read alarm flags of all channels.
+func (d *DS2450) ReadAlarms() ([4]ADCAlarm, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	var alarms [4]ADCAlarm
+	control, err := d.readPage(DS2450PageControl)
+	if err != nil {
+		return alarms, err
+	}
+	for ch := 0; ch < 4; ch++ {
+		alarms[ch].Low = control[ch*2+1]&0b00010000 != 0
+		alarms[ch].High = control[ch*2+1]&0b00100000 != 0
+	}
+	return alarms, nil
+}
+
+// Convert channels selected with mask (bit 0 - A ... bit 3 - D) and read voltages of all channels in V.
+// Preset is applied to all selected channels.
+func (d *DS2450) GetVoltages(mask byte, preset ADCPreset) ([4]float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	var voltages [4]float64
+	if err := d.convert(mask, preset); err != nil {
+		return voltages, err
+	}
+	return d.readVoltages()
+}
+
+// Read voltages of all channels in V without conversion.
+func (d *DS2450) ReadVoltages() ([4]float64, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	return d.readVoltages()
+}
+
+func (d *DS2450) readVoltages() ([4]float64, error) {
+	var voltages [4]float64
+	control, err := d.readPage(DS2450PageControl)
+	if err != nil {
+		return voltages, err
+	}
+	results, err := d.readPage(DS2450PageConversion)
+	if err != nil {
+		return voltages, err
+	}
+	for ch := 0; ch < 4; ch++ {
+		inputRange := 2.56
+		if control[ch*2+1]&0b1 != 0 {
+			inputRange = 5.12
+		}
+		voltages[ch] = float64(binary.LittleEndian.Uint16(results[ch*2:ch*2+2])) / 65536 * inputRange
+	}
+	return voltages, nil
+}
+
+//
+// CONVERT [3Ch]
+//
+// Converts selected channels. Conversion takes 80µs per bit per channel plus 160µs offset,
+// so it's no more than 5.28ms for 4 channels in 16 bits.
+//
+func (d *DS2450) convert(mask byte, preset ADCPreset) error {
+	var readout byte
+	for ch := 0; ch < 4; ch++ {
+		if mask&(1<<ch) != 0 {
+			readout |= byte(preset) << (ch * 2)
+		}
+	}
+	command := []byte{0x3c, mask & 0x0f, readout}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return err
+	}
+	var crc [2]byte
+	if _, err := d.bus.readBytes(crc[0:2]); err != nil {
+		return err
+	}
+	if !checkCRC16(command, crc[0:2]) {
+		return errors.New("convert command crc error")
+	}
+	time.Sleep(6 * time.Millisecond)
+	return nil
+}
+
+//
+// READ MEMORY [AAh]
+//
+// Reads memory from the start of the page up to its end followed by CRC16
+// of the command, address and data.
+//
+func (d *DS2450) readPage(page byte) ([]byte, error) {
+	if page > 3 {
+		return nil, fmt.Errorf("wrong page number: %d", page)
+	}
+	command := []byte{0xaa, page * 8, 0x00}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return nil, err
+	}
+	if _, err := d.bus.writeBytes(command); err != nil {
+		return nil, err
+	}
+	var data = make([]byte, 10)
+	if _, err := d.bus.readBytes(data); err != nil {
+		return nil, err
+	}
+	if !checkCRC16(append(command, data[0:8]...), data[8:10]) {
+		return nil, errors.New("read memory crc error")
+	}
+	return data[0:8], nil
+}
+
+//
+// WRITE MEMORY [55h]
+//
+// Each byte written is confirmed with CRC16: of the command, address and data for the first byte,
+// and of the incremented address and data for the following ones. Then the device reads the byte back.
+//
+func (d *DS2450) writeMemory(address uint16, data []byte) error {
+	if int(address)+len(data) > 32 {
+		return fmt.Errorf("write beyond memory: %d bytes at 0x%02X", len(data), address)
+	}
+	if err := d.bus.matchROM(d.rom); err != nil {
+		return err
+	}
+	header := []byte{0x55, byte(address), byte(address >> 8)}
+	if _, err := d.bus.writeBytes(header); err != nil {
+		return err
+	}
+	for n, b := range data {
+		if err := d.bus.writeByte(b); err != nil {
+			return err
+		}
+		var crc [2]byte
+		if _, err := d.bus.readBytes(crc[0:2]); err != nil {
+			return err
+		}
+		a := address + uint16(n)
+		crcData := []byte{byte(a), byte(a >> 8), b}
+		if n == 0 {
+			crcData = append(header, b)
+		}
+		if !checkCRC16(crcData, crc[0:2]) {
+			return fmt.Errorf("write memory crc error at 0x%02X", a)
+		}
+		if readBack, err := d.bus.readByte(); err != nil {
+			return err
+		} else if readBack != b {
+			return fmt.Errorf("write memory verification failed at 0x%02X (wrote: 0x%02X, read: 0x%02X)", a, b, readBack)
+		}
+	}
+	return nil
+}
+
+// Control/status bytes:
+//
+//	byte 0: OE OC x x RC3 RC2 RC1 RC0 - output enable and control, resolution (0 means 16 bits)
+//	byte 1: POR x AFH AFL AEH AEL x IR - power-on reset, alarm flags and enables, input range
+//
+// Alarm bytes: low threshold, high threshold.
+func decodeADCChannelConfig(control []byte, alarm []byte) *ADCChannelConfig {
+	config := &ADCChannelConfig{
+		Resolution:      control[0] & 0x0f,
+		InputRange:      2.56,
+		OutputControl:   control[0]&0b01000000 != 0,
+		OutputEnable:    control[0]&0b10000000 != 0,
+		AlarmLowEnable:  control[1]&0b00000100 != 0,
+		AlarmHighEnable: control[1]&0b00001000 != 0,
+		AlarmLow:        alarm[0],
+		AlarmHigh:       alarm[1],
+	}
+	if config.Resolution == 0 {
+		config.Resolution = 16
+	}
+	if control[1]&0b1 != 0 {
+		config.InputRange = 5.12
+	}
+	return config
+}
+
+func encodeADCChannelConfig(config *ADCChannelConfig) ([]byte, []byte, error) {
+	if config.Resolution < 1 || config.Resolution > 16 {
+		return nil, nil, fmt.Errorf("wrong resolution: %d", config.Resolution)
+	}
+	control := []byte{config.Resolution & 0x0f, 0x00}
+	if config.OutputControl {
+		control[0] |= 0b01000000
+	}
+	if config.OutputEnable {
+		control[0] |= 0b10000000
+	}
+	switch config.InputRange {
+	case 2.56:
+	case 5.12:
+		control[1] |= 0b1
+	default:
+		return nil, nil, fmt.Errorf("wrong input range: %.2fV", config.InputRange)
+	}
+	if config.AlarmLowEnable {
+		control[1] |= 0b00000100
+	}
+	if config.AlarmHighEnable {
+		control[1] |= 0b00001000
+	}
+	return control, []byte{config.AlarmLow, config.AlarmHigh}, nil
+}
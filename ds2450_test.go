@@ -0,0 +1,30 @@
+package digitemp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestADCChannelConfig(t *testing.T) {
+	config := &ADCChannelConfig{
+		Resolution:      16,
+		InputRange:      5.12,
+		AlarmHighEnable: true,
+		AlarmLow:        0x10,
+		AlarmHigh:       0xc0,
+	}
+	control, alarm, err := encodeADCChannelConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(control, []byte{0x00, 0x09}) || !bytes.Equal(alarm, []byte{0x10, 0xc0}) {
+		t.Errorf("got: % X, % X", control, alarm)
+	}
+	if decoded := decodeADCChannelConfig(control, alarm); *decoded != *config {
+		t.Errorf("got: %+v, expected: %+v", decoded, config)
+	}
+
+	if _, _, err := encodeADCChannelConfig(&ADCChannelConfig{Resolution: 8, InputRange: 3.3}); err == nil {
+		t.Error("wrong input range accepted")
+	}
+}
@@ -0,0 +1,191 @@
+package digitemp
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Chain control bytes of DS28EA00.
+const (
+	chainOff  = 0x3c
+	chainOn   = 0x5a
+	chainDone = 0x96
+)
+
+// DS28EA00 - Digital Thermometer with Sequence Detect and PIO.
+//
+// Temperature is handled exactly as in DS18B20. In addition there are two PIO outputs
+// and chain mode which allows to detect physical order of sensors along a cable.
+type DS28EA00 struct {
+	*TemperatureSensor
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS28EA00",
+		Description: "DS28EA00 - Digital Thermometer with Sequence Detect and PIO",
+		FamilyCodes: []byte{FamilyDS28EA00},
+		New: func(bus *UARTAdapter, rom *ROM) (Device, error) {
+			return NewDS28EA00(bus, rom, true)
+		},
+	})
+}
+
+// Create new DS28EA00 instance. Arguments have the same meaning as in NewTemperatureSensor.
+func NewDS28EA00(bus *UARTAdapter, rom *ROM, required bool) (*DS28EA00, error) {
+	s, err := NewTemperatureSensor(bus, rom, required)
+	if err != nil {
+		return nil, err
+	}
+	if s.familyCode != FamilyDS28EA00 {
+		_ = s.Close()
+		return nil, fmt.Errorf("device %s is not DS28EA00", s.rom)
+	}
+	return &DS28EA00{s}, nil
+}
+
+// PIO ACCESS READ [F5h]
+// Read logic state of PIO pins and output latches.
+func (d *DS28EA00) ReadPIO() (PIOStatus, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if err := d.reset(); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0xf5); err != nil {
+		return 0, err
+	}
+	return d.bus.readPIOStatus()
+}
+
+// PIO ACCESS WRITE [A5h]
+// Write output latches. False turns output transistor on and pulls the pin low.
+// Returns PIO status after the write.
+func (d *DS28EA00) WritePIO(pioA bool, pioB bool) (PIOStatus, error) {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	data := encodePIOLatches(pioA, pioB)
+	if err := d.reset(); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeByte(0xa5); err != nil {
+		return 0, err
+	}
+	if err := d.bus.writeConfirmedByte(data); err != nil {
+		return 0, err
+	}
+	return d.bus.readPIOStatus()
+}
+
+// Put the device into chain ON state. PIOB becomes EN input and PIOA (DONE) is released.
+func (d *DS28EA00) ChainOn() error {
+	return d.chain(chainOn)
+}
+
+// Put the device into chain OFF state.
+func (d *DS28EA00) ChainOff() error {
+	return d.chain(chainOff)
+}
+
+// Put the device into chain DONE state. PIOA (DONE) is pulled low enabling the next device.
+func (d *DS28EA00) ChainDone() error {
+	return d.chain(chainDone)
+}
+
+func (d *DS28EA00) chain(control byte) error {
+	d.bus.lock()
+	defer d.bus.unlock()
+
+	if err := d.reset(); err != nil {
+		return err
+	}
+	return d.bus.chain(control)
+}
+
+// Detect physical order of DS28EA00 devices along a cable.
+//
+// The devices shall be wired in a chain: PIOB (EN) of the first device is tied to GND and PIOA (DONE)
+// of each device is connected to PIOB (EN) of the next one. Returns ROM codes from the first device to the last.
+func DetectSequence(bus *UARTAdapter) ([]*ROM, error) {
+	bus.lock()
+	defer bus.unlock()
+
+	if err := bus.skipROM(); err != nil {
+		return nil, err
+	}
+	if err := bus.chain(chainOn); err != nil {
+		return nil, err
+	}
+
+	sequence, seqErr := bus.detectSequence()
+
+	// always leave chain mode
+	if err := bus.skipROM(); err != nil {
+		return nil, err
+	}
+	if err := bus.chain(chainOff); err != nil {
+		return nil, err
+	}
+	return sequence, seqErr
+}
+
+func (a *UARTAdapter) detectSequence() ([]*ROM, error) {
+	sequence := make([]*ROM, 0)
+	for {
+		rom, err := a.conditionalReadROM()
+		if err != nil {
+			return nil, err
+		}
+		if rom == nil {
+			return sequence, nil
+		}
+		sequence = append(sequence, rom)
+
+		if err := a.matchROM(rom); err != nil {
+			return nil, err
+		}
+		if err := a.chain(chainDone); err != nil {
+			return nil, err
+		}
+	}
+}
+
+//
+// CHAIN [99h]
+//
+// Changes chain state of the addressed devices. Control byte is sent along with its complement
+// and the device confirms it with AAh.
+//
+func (a *UARTAdapter) chain(control byte) error {
+	if err := a.writeByte(0x99); err != nil {
+		return err
+	}
+	return a.writeConfirmedByte(control)
+}
+
+//
+// CONDITIONAL READ ROM [0Fh]
+//
+// Only the device in chain ON state with its EN input (PIOB) pulled low responds. Returns nil if nobody responded.
+//
+func (a *UARTAdapter) conditionalReadROM() (*ROM, error) {
+	if err := a.reset(); err != nil {
+		return nil, err
+	}
+	if err := a.writeByte(0x0f); err != nil {
+		return nil, err
+	}
+	var rom = new(ROM)
+	if _, err := a.readBytes(rom.Code[0:8]); err != nil {
+		return nil, err
+	}
+	if bytes.Count(rom.Code[0:8], []byte{0xff}) == 8 {
+		return nil, nil
+	}
+	if !rom.IsValid() {
+		return nil, fmt.Errorf("conditional read rom crc error")
+	}
+	return rom, nil
+}
@@ -32,21 +32,18 @@ func main() {
 		_ = uart.Close()
 	}()
 
-	sensors := make([]*digitemp.TemperatureSensor, 0)
-
-	log.Println("Searching ROMs")
-	if roms, err := uart.GetConnectedROMs(); err != nil {
+	log.Println("Searching devices")
+	discovery, err := digitemp.Discover(uart)
+	if err != nil {
 		log.Fatal(err)
-	} else {
-		for n, rom := range roms {
-			log.Printf("%d: %s\n", n, rom)
-			if sensor, err := digitemp.NewTemperatureSensor(uart, rom, true); err != nil {
-				log.Fatal(err)
-			} else {
-				sensors = append(sensors, sensor)
-			}
-		}
 	}
+	for n, dev := range discovery.Devices {
+		log.Printf("%d: %s %s\n", n, dev.GetROM(), dev.GetName())
+	}
+	for _, failure := range discovery.Failed {
+		log.Printf("failed: %s\n", failure)
+	}
+	sensors := discovery.TemperatureSensors()
 
 	for _, sensor := range sensors {
 		log.Printf("====================================================\n")
@@ -65,7 +62,8 @@ func main() {
 	go func() {
 		measurements := make([]string, len(sensors))
 		for {
-			if err := uart.MeasureTemperatureAll(); err != nil {
+			tConv, err := uart.MeasureTemperatureAll()
+			if err != nil {
 				log.Print(err)
 				continue
 			}
@@ -76,7 +74,7 @@ func main() {
 					measurements[n] = fmt.Sprintf("%.02fºC", tc)
 				}
 			}
-			log.Println(strings.Join(measurements, "   "))
+			log.Printf("%s   (%s)", strings.Join(measurements, "   "), tConv)
 			time.Sleep(3 * time.Second)
 		}
 	}()
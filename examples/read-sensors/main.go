@@ -32,21 +32,18 @@ func main() {
 		_ = uart.Close()
 	}()
 
-	sensors := make([]*digitemp.TemperatureSensor, 0)
-
-	log.Println("Searching ROMs")
-	if roms, err := uart.GetConnectedROMs(); err != nil {
+	log.Println("Searching devices")
+	discovery, err := digitemp.Discover(uart)
+	if err != nil {
 		log.Fatal(err)
-	} else {
-		for n, rom := range roms {
-			log.Printf("%d: %s\n", n, rom)
-			if sensor, err := digitemp.NewTemperatureSensor(uart, rom, true); err != nil {
-				log.Fatal(err)
-			} else {
-				sensors = append(sensors, sensor)
-			}
-		}
 	}
+	for n, dev := range discovery.Devices {
+		log.Printf("%d: %s %s\n", n, dev.GetROM(), dev.GetName())
+	}
+	for _, failure := range discovery.Failed {
+		log.Printf("failed: %s\n", failure)
+	}
+	sensors := discovery.TemperatureSensors()
 
 	for _, sensor := range sensors {
 		log.Printf("====================================================\n")
@@ -19,7 +19,11 @@ func main() {
 		log.Fatal(err)
 	} else {
 		for n, rom := range roms {
-			log.Printf("%d: %s\n", n, rom)
+			if f := rom.FamilyInfo(); f != nil {
+				log.Printf("%d: %s %s (%s)\n", n, rom, f, f.Category)
+			} else {
+				log.Printf("%d: %s unknown family 0x%02X\n", n, rom, rom.Family())
+			}
 		}
 	}
 }
@@ -0,0 +1,100 @@
+package digitemp
+
+import (
+	"strings"
+)
+
+// Kind of 1-Wire device.
+type FamilyCategory string
+
+const (
+	CategoryIdentification FamilyCategory = "identification"
+	CategoryMemory         FamilyCategory = "memory"
+	CategorySecureMemory   FamilyCategory = "secure memory"
+	CategoryTemperature    FamilyCategory = "temperature"
+	CategoryDataLogger     FamilyCategory = "data logger"
+	CategorySwitch         FamilyCategory = "switch"
+	CategoryCounter        FamilyCategory = "counter"
+	CategoryADC            FamilyCategory = "a/d converter"
+	CategoryBattery        FamilyCategory = "battery monitor"
+	CategoryClock          FamilyCategory = "real-time clock"
+	CategoryPotentiometer  FamilyCategory = "potentiometer"
+	CategoryCoupler        FamilyCategory = "bus coupler"
+)
+
+// Publicly documented 1-Wire device family.
+type FamilyInfo struct {
+	Code        byte
+	Parts       []string // part numbers sharing the family code
+	Description string
+	Category    FamilyCategory
+}
+
+// Returns part numbers and description, e.g. "DS18B20 - Programmable Resolution Digital Thermometer".
+func (f *FamilyInfo) String() string {
+	return strings.Join(f.Parts, "/") + " - " + f.Description
+}
+
+var families = []FamilyInfo{
+	{0x01, []string{"DS2401", "DS2411", "DS1990A"}, "Silicon Serial Number", CategoryIdentification},
+	{0x02, []string{"DS1991"}, "MultiKey iButton", CategorySecureMemory},
+	{0x04, []string{"DS2404", "DS1994"}, "EconoRAM Time Chip", CategoryClock},
+	{0x05, []string{"DS2405"}, "Addressable Switch", CategorySwitch},
+	{0x06, []string{"DS1993"}, "4Kb Memory iButton", CategoryMemory},
+	{0x08, []string{"DS1992"}, "1Kb Memory iButton", CategoryMemory},
+	{0x09, []string{"DS2502", "DS1982"}, "1Kb Add-Only Memory", CategoryMemory},
+	{0x0A, []string{"DS1995"}, "16Kb Memory iButton", CategoryMemory},
+	{0x0B, []string{"DS2505", "DS1985"}, "16Kb Add-Only Memory", CategoryMemory},
+	{0x0C, []string{"DS1996"}, "64Kb Memory iButton", CategoryMemory},
+	{0x0F, []string{"DS2506", "DS1986"}, "64Kb Add-Only Memory", CategoryMemory},
+	{0x10, []string{"DS18S20", "DS1820", "DS1920"}, "High-precision Digital Thermometer", CategoryTemperature},
+	{0x12, []string{"DS2406", "DS2407"}, "Dual Addressable Switch with 1Kb Memory", CategorySwitch},
+	{0x14, []string{"DS2430A", "DS1971"}, "256-bit EEPROM", CategoryMemory},
+	{0x16, []string{"DS1954", "DS1957"}, "Cryptographic iButton", CategorySecureMemory},
+	{0x18, []string{"DS1963S", "DS1962"}, "SHA iButton", CategorySecureMemory},
+	{0x1A, []string{"DS1963L"}, "4Kb Monetary iButton", CategoryMemory},
+	{0x1B, []string{"DS2436"}, "Battery ID/Monitor", CategoryBattery},
+	{0x1C, []string{"DS28E04-100"}, "4Kb EEPROM with PIO", CategoryMemory},
+	{0x1D, []string{"DS2423"}, "4Kb RAM with Counter", CategoryCounter},
+	{0x1E, []string{"DS2437"}, "Smart Battery Monitor", CategoryBattery},
+	{0x1F, []string{"DS2409"}, "MicroLAN Coupler", CategoryCoupler},
+	{0x20, []string{"DS2450"}, "Quad A/D Converter", CategoryADC},
+	{0x21, []string{"DS1921"}, "Thermochron iButton", CategoryDataLogger},
+	{0x22, []string{"DS1822"}, "Econo Digital Thermometer", CategoryTemperature},
+	{0x23, []string{"DS2433", "DS1973"}, "4Kb EEPROM", CategoryMemory},
+	{0x24, []string{"DS2415", "DS1904"}, "Real-Time Clock", CategoryClock},
+	{0x26, []string{"DS2438"}, "Smart Battery Monitor", CategoryBattery},
+	{0x27, []string{"DS2417"}, "Real-Time Clock with Interrupt", CategoryClock},
+	{0x28, []string{"DS18B20"}, "Programmable Resolution Digital Thermometer", CategoryTemperature},
+	{0x29, []string{"DS2408"}, "8-Channel Addressable Switch", CategorySwitch},
+	{0x2C, []string{"DS2890"}, "Digital Potentiometer", CategoryPotentiometer},
+	{0x2D, []string{"DS2431", "DS1972"}, "1Kb EEPROM", CategoryMemory},
+	{0x2E, []string{"DS2770"}, "Battery Monitor and Charge Controller", CategoryBattery},
+	{0x30, []string{"DS2760", "DS2761", "DS2762"}, "High-Precision Li+ Battery Monitor", CategoryBattery},
+	{0x31, []string{"DS2720"}, "Li+ Protection IC", CategoryBattery},
+	{0x32, []string{"DS2780"}, "Stand-Alone Fuel Gauge", CategoryBattery},
+	{0x33, []string{"DS2432", "DS1961S"}, "1Kb EEPROM with SHA-1 Engine", CategorySecureMemory},
+	{0x35, []string{"DS2755"}, "Fuel Gauge", CategoryBattery},
+	{0x36, []string{"DS2740"}, "High-Precision Coulomb Counter", CategoryBattery},
+	{0x37, []string{"DS1977"}, "Password-Protected 32Kb EEPROM", CategorySecureMemory},
+	{0x3A, []string{"DS2413"}, "Dual Channel Addressable Switch", CategorySwitch},
+	{0x3B, []string{"DS1825", "MAX31826", "MAX31850", "MAX31851"}, "Digital Thermometer / Thermocouple Converter", CategoryTemperature},
+	{0x41, []string{"DS1922", "DS1923", "DS2422"}, "Temperature/Humidity Logger", CategoryDataLogger},
+	{0x42, []string{"DS28EA00"}, "Digital Thermometer with Sequence Detect and PIO", CategoryTemperature},
+	{0x43, []string{"DS28EC20"}, "20Kb EEPROM", CategoryMemory},
+	{0x51, []string{"DS2751"}, "Multichemistry Battery Fuel Gauge", CategoryBattery},
+	{0x81, []string{"DS1420"}, "Serial ID Button", CategoryIdentification},
+}
+
+var familyByCode = func() map[byte]*FamilyInfo {
+	m := make(map[byte]*FamilyInfo, len(families))
+	for n := range families {
+		m[families[n].Code] = &families[n]
+	}
+	return m
+}()
+
+// Get description of a publicly documented device family. Returns nil if the family is unknown.
+func LookupFamily(code byte) *FamilyInfo {
+	return familyByCode[code]
+}
@@ -0,0 +1,194 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Origin of a DS18B20 chip.
+type ChipOrigin int
+
+const (
+	ChipUnknown ChipOrigin = iota
+	ChipGenuine
+	ChipClone
+)
+
+func (o ChipOrigin) String() string {
+	switch o {
+	case ChipGenuine:
+		return "genuine"
+	case ChipClone:
+		return "clone"
+	default:
+		return "unknown"
+	}
+}
+
+// Conversion time range of genuine DS18B20 in 12-bit resolution.
+const (
+	genuineMinConversionTime = 450 * time.Millisecond
+	genuineMaxConversionTime = 750 * time.Millisecond
+)
+
+// Result of DS18B20 fingerprinting.
+//
+// Signatures are based on public research of DS18B20 clones
+// (see https://github.com/cpetrich/counterfeit_DS18B20):
+//
+//   - genuine chips have ROM codes of form 28-xx-xx-xx-xx-00-00-xx;
+//   - genuine chips set reserved scratchpad byte 6 to 0x10 - (temperature & 0x0F) after 12-bit conversion
+//     and keep bytes 5 and 7 at 0xFF and 0x10;
+//   - genuine chips support all 4 resolutions, some clones are fixed at 12 bits;
+//   - genuine chips take 450..750ms for 12-bit conversion;
+//   - genuine chips are reported to answer undocumented command [93h], many clones do not.
+//
+// Clone families are named as in the research, see cloneSignatures.
+type Fingerprint struct {
+	Origin                 ChipOrigin
+	CloneFamily            string        // clone family name according to the research, empty if not known
+	GenuineROM             bool          // ROM code matches genuine pattern
+	ReservedBytesValid     bool          // reserved scratchpad bytes behave as in genuine chips
+	ReservedBytesFixed     bool          // reserved byte 6 keeps its power-on value 0x0C after conversion
+	ResolutionConfigurable bool          // resolution can be changed
+	ConversionTime         time.Duration // 12-bit conversion time, zero if not measured (parasitic mode)
+	UndocumentedCommand    bool          // device answers undocumented command [93h]
+}
+
+func (f *Fingerprint) String() string {
+	if f.CloneFamily != "" {
+		return fmt.Sprintf("%s (family %s)", f.Origin, f.CloneFamily)
+	}
+	return f.Origin.String()
+}
+
+// Check if the DS18B20 is genuine or a clone.
+//
+// It changes resolution during the check and restores original scratchpad settings afterwards,
+// even if the check fails. EEPROM is not touched.
+func (s *TemperatureSensor) Fingerprint() (f *Fingerprint, err error) {
+	if s.familyCode != FamilyDS18B20 {
+		return nil, errors.New("fingerprinting is supported for DS18B20 only")
+	}
+
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	original, err := s.readDecodedScratchpad()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if restoreErr := s.writeVerifiedSettings(original); restoreErr != nil && err == nil {
+			f, err = nil, restoreErr
+		}
+	}()
+
+	f = &Fingerprint{
+		GenuineROM: s.rom.Code[5] == 0x00 && s.rom.Code[6] == 0x00,
+	}
+
+	// resolution
+	probe := *original
+	probe.Config = original.Config.WithResolution(Resolution9bits)
+	if err := s.writeScratchpad(probe.settings()); err != nil {
+		return nil, err
+	}
+	if sp, err := s.readDecodedScratchpad(); err != nil {
+		return nil, err
+	} else {
+		f.ResolutionConfigurable = sp.Config.Resolution() == Resolution9bits
+	}
+
+	// conversion time and reserved bytes in 12 bits resolution
+	probe.Config = original.Config.WithResolution(Resolution12bits)
+	if err := s.writeScratchpad(probe.settings()); err != nil {
+		return nil, err
+	}
+	if err := s.reset(); err != nil {
+		return nil, err
+	}
+	if err := s.bus.writeByte(0x44); err != nil {
+		return nil, err
+	}
+	startedAt := time.Now()
+	if err := s.wait(genuineMaxConversionTime + 250*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if !s.parasiticMode {
+		f.ConversionTime = time.Since(startedAt)
+	}
+	if sp, err := s.readDecodedScratchpad(); err != nil {
+		return nil, err
+	} else {
+		expected := 0x10 - byte(sp.Temperature&0x0f)
+		f.ReservedBytesValid = sp.Reserved[0] == 0xff && sp.Reserved[2] == 0x10 && sp.Reserved[1] == expected
+		f.ReservedBytesFixed = sp.Reserved[1] == 0x0c && expected != 0x0c
+	}
+
+	// undocumented command
+	if err := s.reset(); err != nil {
+		return nil, err
+	}
+	if err := s.bus.writeByte(0x93); err != nil {
+		return nil, err
+	}
+	if b, err := s.bus.readByte(); err != nil {
+		return nil, err
+	} else {
+		f.UndocumentedCommand = b != 0xff
+	}
+
+	f.classify(s.rom)
+	return f, nil
+}
+
+// Signature of a clone family.
+type cloneSignature struct {
+	family string
+	match  func(rom *ROM, f *Fingerprint) bool
+}
+
+// Clone families in order of the check, most specific signatures first:
+//
+//	D - ROM code of form 28-FF-xx-xx-xx-xx-xx-xx
+//	C - genuine ROM pattern, 12-bit conversion faster than genuine
+//	B - genuine ROM pattern, reserved byte 6 stays at 0x0C after conversion
+//	E - non-genuine ROM pattern and resolution fixed at 12 bits
+var cloneSignatures = []cloneSignature{
+	{"D", func(rom *ROM, f *Fingerprint) bool {
+		return rom.Code[1] == 0xff && !f.GenuineROM
+	}},
+	{"C", func(rom *ROM, f *Fingerprint) bool {
+		return f.GenuineROM && f.ConversionTime != 0 && f.ConversionTime < genuineMinConversionTime
+	}},
+	{"B", func(rom *ROM, f *Fingerprint) bool {
+		return f.GenuineROM && f.ReservedBytesFixed
+	}},
+	{"E", func(rom *ROM, f *Fingerprint) bool {
+		return !f.GenuineROM && !f.ResolutionConfigurable
+	}},
+}
+
+// Decide on chip's origin based on collected signatures.
+func (f *Fingerprint) classify(rom *ROM) {
+	for _, signature := range cloneSignatures {
+		if signature.match(rom, f) {
+			f.Origin = ChipClone
+			f.CloneFamily = signature.family
+			return
+		}
+	}
+
+	conversionValid := f.ConversionTime == 0 ||
+		(f.ConversionTime >= genuineMinConversionTime && f.ConversionTime <= genuineMaxConversionTime)
+	switch {
+	case f.GenuineROM && f.ReservedBytesValid && f.ResolutionConfigurable && conversionValid && f.UndocumentedCommand:
+		f.Origin = ChipGenuine
+	case !f.ReservedBytesValid || !f.ResolutionConfigurable || !conversionValid:
+		f.Origin = ChipClone
+	default:
+		f.Origin = ChipUnknown
+	}
+}
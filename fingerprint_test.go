@@ -0,0 +1,61 @@
+package digitemp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFingerprint_classify(t *testing.T) {
+	genuineROM, _ := NewROMFromString("28AB12CD34000042")
+	cloneROM, _ := NewROMFromString("28FF4C6A01160487")
+	otherROM, _ := NewROMFromString("2811223344556656")
+
+	var testcases = []struct {
+		rom         *ROM
+		fingerprint Fingerprint
+		origin      ChipOrigin
+		family      string
+	}{
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesValid: true, ResolutionConfigurable: true, ConversionTime: 600 * time.Millisecond, UndocumentedCommand: true}, ChipGenuine, ""},
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesValid: true, ResolutionConfigurable: true, UndocumentedCommand: true}, ChipGenuine, ""},
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesValid: false, ResolutionConfigurable: true, UndocumentedCommand: true}, ChipClone, ""},
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesValid: true, ResolutionConfigurable: true}, ChipUnknown, ""},
+		{otherROM, Fingerprint{ReservedBytesValid: true, ResolutionConfigurable: true, ConversionTime: 800 * time.Millisecond}, ChipClone, ""},
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesFixed: true, ResolutionConfigurable: true, ConversionTime: 600 * time.Millisecond}, ChipClone, "B"},
+		{genuineROM, Fingerprint{GenuineROM: true, ReservedBytesValid: true, ResolutionConfigurable: true, ConversionTime: 30 * time.Millisecond}, ChipClone, "C"},
+		{cloneROM, Fingerprint{ReservedBytesValid: true, ResolutionConfigurable: true}, ChipClone, "D"},
+		{cloneROM, Fingerprint{ResolutionConfigurable: false, ConversionTime: 30 * time.Millisecond}, ChipClone, "D"},
+		{otherROM, Fingerprint{ReservedBytesValid: true, ResolutionConfigurable: false}, ChipClone, "E"},
+	}
+	for n, tc := range testcases {
+		f := tc.fingerprint
+		f.classify(tc.rom)
+		if f.Origin != tc.origin || f.CloneFamily != tc.family {
+			t.Errorf("(%d, got: %s, expected: %s %s)", n, &f, tc.origin, tc.family)
+		}
+	}
+}
+
+func TestTemperatureSensor_FingerprintRestoresSettings(t *testing.T) {
+	device := newFakeThermometer("2811223344556656")
+	device.scratchpad[4] = 0x3f // 10 bits
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+	if f, err := sensor.Fingerprint(); err != nil {
+		t.Fatal(err)
+	} else if !f.ResolutionConfigurable || !f.ReservedBytesFixed {
+		t.Errorf("got: %+v", f)
+	}
+	if device.scratchpad[4] != 0x3f {
+		t.Errorf("settings not restored: 0x%02X", device.scratchpad[4])
+	}
+
+	// bus fails right after 12-bit conversion
+	device.onConvert = func() { device.bogusReads = 1 }
+	if _, err := sensor.Fingerprint(); err == nil {
+		t.Error("error expected")
+	}
+	if device.scratchpad[4] != 0x3f {
+		t.Errorf("settings not restored after failure: 0x%02X", device.scratchpad[4])
+	}
+}
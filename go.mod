@@ -1,6 +1,6 @@
 module digitemp
 
-go 1.15
+go 1.16
 
 require (
 	go.bug.st/serial v1.1.1
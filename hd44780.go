@@ -0,0 +1,312 @@
+package digitemp
+
+import (
+	"fmt"
+	"time"
+)
+
+// Wiring of HD44780 display to DS2408 PIO channels P0..P7. Display is driven in 4-bit mode.
+// Set RW or Backlight to -1 if the pin is not connected (RW tied to GND).
+// Each pin shall use its own channel.
+type LCDPinout struct {
+	RS                 int
+	RW                 int
+	E                  int
+	Data               [4]int // D4..D7
+	Backlight          int
+	BacklightActiveLow bool // backlight is on when PIO pulls the pin low
+}
+
+// Pinout of common DS2408 LCD boards: D4..D7 on P4..P7, RS on P3, E on P2, RW on P1
+// and backlight transistor on P0.
+var DefaultLCDPinout = LCDPinout{
+	RS:                 3,
+	RW:                 1,
+	E:                  2,
+	Data:               [4]int{4, 5, 6, 7},
+	Backlight:          0,
+	BacklightActiveLow: true,
+}
+
+// HD44780 instructions.
+const (
+	lcdClear       = 0x01
+	lcdHome        = 0x02
+	lcdEntryMode   = 0x04
+	lcdDisplay     = 0x08
+	lcdFunctionSet = 0x20
+	lcdSetCGRAM    = 0x40
+	lcdSetDDRAM    = 0x80
+)
+
+// HD44780 compatible character LCD connected to DS2408.
+//
+// Each byte is sent as two nibbles with E strobe, so it takes 4 PIO writes. They are batched
+// into a single channel access transaction, which makes updating whole display quick.
+type LCD struct {
+	sw        *DS2408
+	pinout    LCDPinout
+	columns   int
+	rows      int
+	backlight bool
+	display   byte
+	address   byte // DDRAM address of the cursor
+}
+
+// Create LCD with given size, e.g. 20 columns and 4 rows, and initialize it.
+func NewLCD(sw *DS2408, pinout LCDPinout, columns int, rows int) (*LCD, error) {
+	if columns < 1 || columns > 40 || rows < 1 || rows > 4 {
+		return nil, fmt.Errorf("wrong display size: %dx%d", columns, rows)
+	}
+	if err := pinout.validate(); err != nil {
+		return nil, err
+	}
+	l := &LCD{
+		sw:        sw,
+		pinout:    pinout,
+		columns:   columns,
+		rows:      rows,
+		backlight: true,
+		display:   0b100,
+	}
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Initialize display in 4-bit mode, clear it and turn it on without cursor.
+func (l *LCD) Init() error {
+	time.Sleep(15 * time.Millisecond)
+
+	// reset sequence: 8-bit function set three times, then switch to 4-bit mode
+	for _, delay := range []time.Duration{5 * time.Millisecond, 200 * time.Microsecond, 200 * time.Microsecond} {
+		if _, err := l.sw.WritePIOSequence(l.encodeNibble(false, 0x3)); err != nil {
+			return err
+		}
+		time.Sleep(delay)
+	}
+	if _, err := l.sw.WritePIOSequence(l.encodeNibble(false, 0x2)); err != nil {
+		return err
+	}
+
+	var functionSet byte = lcdFunctionSet
+	if l.rows > 1 {
+		functionSet |= 0b1000
+	}
+	if err := l.command(functionSet, lcdDisplay|l.display, lcdEntryMode|0b10); err != nil {
+		return err
+	}
+	return l.Clear()
+}
+
+// Clear display and move cursor home.
+func (l *LCD) Clear() error {
+	if err := l.command(lcdClear); err != nil {
+		return err
+	}
+	l.address = 0
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+// Move cursor home and undo display shift.
+func (l *LCD) Home() error {
+	if err := l.command(lcdHome); err != nil {
+		return err
+	}
+	l.address = 0
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+// Move cursor to the column and row, both start with 0.
+func (l *LCD) SetCursor(column int, row int) error {
+	if column < 0 || column >= l.columns || row < 0 || row >= l.rows {
+		return fmt.Errorf("wrong cursor position: %d, %d", column, row)
+	}
+	address := l.ddramAddress(column, row)
+	if err := l.command(lcdSetDDRAM | address); err != nil {
+		return err
+	}
+	l.address = address
+	return nil
+}
+
+// Turn display, cursor and cursor blinking on or off.
+func (l *LCD) SetDisplay(display bool, cursor bool, blink bool) error {
+	l.display = 0
+	if display {
+		l.display |= 0b100
+	}
+	if cursor {
+		l.display |= 0b010
+	}
+	if blink {
+		l.display |= 0b001
+	}
+	return l.command(lcdDisplay | l.display)
+}
+
+// Turn backlight on or off.
+func (l *LCD) SetBacklight(on bool) error {
+	if l.pinout.Backlight < 0 {
+		return fmt.Errorf("backlight is not connected")
+	}
+	l.backlight = on
+	_, err := l.sw.WritePIO(l.idle())
+	return err
+}
+
+// Write string at the cursor position. Characters are sent as is,
+// so only ASCII and codes of display's character set shall be used.
+func (l *LCD) Write(s string) error {
+	if err := l.data([]byte(s)); err != nil {
+		return err
+	}
+	l.advance(len(s))
+	return nil
+}
+
+// Write text at the beginning of the row, padding it with spaces to the whole row.
+func (l *LCD) WriteLine(row int, s string) error {
+	line := []byte(s)
+	if len(line) > l.columns {
+		line = line[:l.columns]
+	}
+	for len(line) < l.columns {
+		line = append(line, ' ')
+	}
+	if err := l.SetCursor(0, row); err != nil {
+		return err
+	}
+	if err := l.data(line); err != nil {
+		return err
+	}
+	l.advance(len(line))
+	return nil
+}
+
+// Define custom character 0..7 with 5x8 pattern, one byte per row, lower 5 bits used.
+// Write byte with the code of the character to show it. Cursor position is kept.
+func (l *LCD) CreateChar(location byte, pattern [8]byte) error {
+	if location > 7 {
+		return fmt.Errorf("wrong custom character: %d", location)
+	}
+	if err := l.command(lcdSetCGRAM | location<<3); err != nil {
+		return err
+	}
+	if err := l.data(pattern[:]); err != nil {
+		return err
+	}
+	// back to display memory at the cursor position
+	return l.command(lcdSetDDRAM | l.address)
+}
+
+func (l *LCD) command(instructions ...byte) error {
+	var sequence []byte
+	for _, instruction := range instructions {
+		sequence = append(sequence, l.encode(false, instruction)...)
+	}
+	_, err := l.sw.WritePIOSequence(sequence)
+	return err
+}
+
+func (l *LCD) data(data []byte) error {
+	var sequence []byte
+	for _, b := range data {
+		sequence = append(sequence, l.encode(true, b)...)
+	}
+	_, err := l.sw.WritePIOSequence(sequence)
+	return err
+}
+
+// DDRAM address of the position. Rows 2 and 3 continue rows 0 and 1.
+func (l *LCD) ddramAddress(column int, row int) byte {
+	offset := 0
+	if row%2 == 1 {
+		offset = 0x40
+	}
+	if row >= 2 {
+		offset += l.columns
+	}
+	return byte(offset + column)
+}
+
+// Move cursor address by n characters the way the display does: in 2-line mode
+// DDRAM is two 40-character lines at 00h and 40h, otherwise one 80-character line.
+func (l *LCD) advance(n int) {
+	if l.rows == 1 {
+		l.address = byte((int(l.address) + n) % 80)
+		return
+	}
+	line, offset := int(l.address/0x40), int(l.address%0x40)
+	position := (line*40 + offset + n) % 80
+	l.address = byte(position/40*0x40 + position%40)
+}
+
+// Check channels are in P0..P7 range and don't overlap.
+func (p *LCDPinout) validate() error {
+	pins := []struct {
+		name     string
+		channel  int
+		optional bool
+	}{
+		{"RS", p.RS, false},
+		{"RW", p.RW, true},
+		{"E", p.E, false},
+		{"D4", p.Data[0], false},
+		{"D5", p.Data[1], false},
+		{"D6", p.Data[2], false},
+		{"D7", p.Data[3], false},
+		{"backlight", p.Backlight, true},
+	}
+	used := make(map[int]string, len(pins))
+	for _, pin := range pins {
+		if pin.optional && pin.channel == -1 {
+			continue
+		}
+		if pin.channel < 0 || pin.channel > 7 {
+			return fmt.Errorf("wrong channel of %s pin: %d", pin.name, pin.channel)
+		}
+		if other, ok := used[pin.channel]; ok {
+			return fmt.Errorf("%s and %s pins use the same channel P%d", other, pin.name, pin.channel)
+		}
+		used[pin.channel] = pin.name
+	}
+	return nil
+}
+
+// Encode byte as PIO writes: high nibble then low nibble, each latched on falling edge of E.
+func (l *LCD) encode(rs bool, b byte) []byte {
+	return append(l.encodeNibble(rs, b>>4), l.encodeNibble(rs, b&0x0f)...)
+}
+
+func (l *LCD) encodeNibble(rs bool, nibble byte) []byte {
+	pio := l.idle()
+	if rs {
+		pio |= 1 << l.pinout.RS
+	}
+	for bit, channel := range l.pinout.Data {
+		if nibble&(1<<bit) != 0 {
+			pio |= 1 << channel
+		} else {
+			pio &^= 1 << channel
+		}
+	}
+	return []byte{pio | 1<<l.pinout.E, pio}
+}
+
+// PIO state between writes: E, RS and RW low, backlight as requested, unused channels released.
+func (l *LCD) idle() byte {
+	pio := byte(0xff)
+	pio &^= 1 << l.pinout.E
+	pio &^= 1 << l.pinout.RS
+	if l.pinout.RW >= 0 {
+		pio &^= 1 << l.pinout.RW
+	}
+	if l.pinout.Backlight >= 0 && l.backlight == l.pinout.BacklightActiveLow {
+		pio &^= 1 << l.pinout.Backlight
+	}
+	return pio
+}
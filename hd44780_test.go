@@ -0,0 +1,108 @@
+package digitemp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLCDEncode(t *testing.T) {
+	l := &LCD{pinout: DefaultLCDPinout, columns: 20, rows: 4, backlight: true}
+
+	if got := l.encode(true, 'A'); !bytes.Equal(got, []byte{0x4c, 0x48, 0x1c, 0x18}) {
+		t.Errorf("data: % X", got)
+	}
+	l.backlight = false
+	if got := l.encode(false, lcdClear); !bytes.Equal(got, []byte{0x05, 0x01, 0x15, 0x11}) {
+		t.Errorf("command: % X", got)
+	}
+}
+
+func TestLCDAddress(t *testing.T) {
+	l := &LCD{columns: 20, rows: 4}
+	for row, expected := range []byte{0x00, 0x40, 0x14, 0x54} {
+		if got := l.ddramAddress(0, row); got != expected {
+			t.Errorf("row %d: 0x%02X, expected: 0x%02X", row, got, expected)
+		}
+	}
+}
+
+func TestLCDPinout_validate(t *testing.T) {
+	valid := DefaultLCDPinout
+	noRW := DefaultLCDPinout
+	noRW.RW, noRW.Backlight = -1, -1
+	outOfRange := DefaultLCDPinout
+	outOfRange.E = 8
+	overlap := DefaultLCDPinout
+	overlap.RS = 4
+	missing := DefaultLCDPinout
+	missing.RS = -1
+
+	var testcases = []struct {
+		pinout LCDPinout
+		valid  bool
+	}{
+		{valid, true},
+		{noRW, true},
+		{outOfRange, false},
+		{overlap, false},
+		{missing, false},
+	}
+	for n, tc := range testcases {
+		if err := tc.pinout.validate(); (err == nil) != tc.valid {
+			t.Errorf("(%d, got: %v)", n, err)
+		}
+	}
+}
+
+func TestLCD_advance(t *testing.T) {
+	var testcases = []struct {
+		rows     int
+		address  byte
+		n        int
+		expected byte
+	}{
+		{4, 0x00, 20, 0x14},
+		{4, 0x14, 25, 0x45},
+		{4, 0x60, 10, 0x02},
+		{1, 0x40, 50, 0x22},
+	}
+	for n, tc := range testcases {
+		l := &LCD{columns: 20, rows: tc.rows, address: tc.address}
+		if l.advance(tc.n); l.address != tc.expected {
+			t.Errorf("(%d, got: 0x%02X, expected: 0x%02X)", n, l.address, tc.expected)
+		}
+	}
+}
+
+func TestLCD_CreateChar(t *testing.T) {
+	var written []byte
+	r, _ := NewROMFromString("291122334455666B")
+	device := &fakeDevice{rom: *r}
+	var channelWrite func(data []byte)
+	channelWrite = func(data []byte) {
+		written = append(written, data[0])
+		device.send(0xaa, data[0])
+		device.expect(2, channelWrite)
+	}
+	device.onFunction = func(d *fakeDevice, command byte) {
+		if command == 0x5a {
+			d.expect(2, channelWrite)
+		}
+	}
+	sw, err := NewDS2408(newFakeBus(device), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &LCD{sw: sw, pinout: DefaultLCDPinout, columns: 20, rows: 4, backlight: true}
+
+	if err := l.SetCursor(5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.CreateChar(2, [8]byte{}); err != nil {
+		t.Fatal(err)
+	}
+	last := l.encode(false, lcdSetDDRAM|0x45)
+	if len(written) < 4 || !bytes.Equal(written[len(written)-4:], last) {
+		t.Errorf("cursor not restored, last writes: % X", written[len(written)-4:])
+	}
+}
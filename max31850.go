@@ -0,0 +1,206 @@
+package digitemp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MAX31850/MAX31851 share family code with DS1825.
+// They are told apart by the upper bits of the configuration byte: these are always 1 in MAX31850.
+const FamilyMAX31850 = 0x3b
+
+// Thermocouple temperature reading of MAX31850/MAX31851.
+type ThermocoupleReading struct {
+	Thermocouple Temperature // thermocouple temperature, 0.25ºC resolution
+	ColdJunction Temperature // cold-junction (device) temperature, 0.0625ºC resolution
+	Fault        bool        // any fault detected, thermocouple temperature is not valid
+	OpenCircuit  bool        // thermocouple is open
+	ShortToGND   bool        // thermocouple is shorted to GND
+	ShortToVDD   bool        // thermocouple is shorted to VDD
+	AddressPins  byte        // state of AD3..AD0 pins
+}
+
+// MAX31850/MAX31851 - Cold-Junction Compensated, 1-Wire Thermocouple-to-Digital Converter.
+type MAX31850 struct {
+	bus           *UARTAdapter
+	rom           *ROM
+	parasiticMode bool
+	addressPins   byte
+	tConv         time.Duration // temperature conversion time
+}
+
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS1825/MAX31850",
+		Description: "DS1825 - Digital Thermometer with Address Pins / MAX31850 - Thermocouple-to-Digital Converter",
+		FamilyCodes: []byte{FamilyMAX31850},
+		New:         newFamily3BDevice,
+	})
+}
+
+// Create DS1825 temperature sensor or MAX31850 thermocouple converter depending on the configuration byte.
+func newFamily3BDevice(bus *UARTAdapter, rom *ROM) (Device, error) {
+	bus.lock()
+	var data []byte
+	err := bus.matchROM(rom)
+	if err == nil {
+		data, err = bus.readScratchpad()
+	}
+	bus.unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if isMAX31850(data) {
+		return NewMAX31850(bus, rom)
+	}
+	s, err := NewTemperatureSensor(bus, rom, true)
+	if err != nil {
+		return nil, err
+	}
+	s.description = "DS1825 - Programmable Resolution Digital Thermometer with Address Pins"
+	return s, nil
+}
+
+// Create new MAX31850/MAX31851 instance.
+func NewMAX31850(bus *UARTAdapter, rom *ROM) (*MAX31850, error) {
+	if rom == nil || rom.Family() != FamilyMAX31850 {
+		return nil, errors.New("MAX31850 requires ROM code of family 0x3B")
+	}
+	m := &MAX31850{
+		bus:   bus,
+		rom:   rom,
+		tConv: 100 * time.Millisecond,
+	}
+
+	m.bus.lock()
+	defer m.bus.unlock()
+
+	if online, err := m.bus.isConnected(m.rom); err != nil {
+		return nil, err
+	} else if !online {
+		return nil, fmt.Errorf("device with ROM %s not found", m.rom)
+	}
+
+	if err := m.bus.matchROM(m.rom); err != nil {
+		return nil, err
+	}
+	if pm, err := m.bus.readPowerSupply(); err != nil {
+		return nil, err
+	} else {
+		m.parasiticMode = pm
+	}
+
+	if r, err := m.readScratchpad(); err != nil {
+		return nil, err
+	} else {
+		m.addressPins = r.AddressPins
+	}
+	return m, nil
+}
+
+func (m *MAX31850) GetROM() *ROM {
+	return m.rom
+}
+
+func (m *MAX31850) GetFamilyCode() byte {
+	return FamilyMAX31850
+}
+
+func (m *MAX31850) GetName() string {
+	return "MAX31850/MAX31851 - Thermocouple-to-Digital Converter"
+}
+
+func (m *MAX31850) IsParasiticMode() bool {
+	return m.parasiticMode
+}
+
+// Get state of hardware address pins AD3..AD0 read during initialization.
+// It tells which physical channel the converter serves.
+func (m *MAX31850) GetAddressPins() byte {
+	return m.addressPins
+}
+
+func (m *MAX31850) Close() error {
+	return nil
+}
+
+// Measure temperature and read from scratchpad.
+// Check reading's Fault flag before using thermocouple temperature.
+func (m *MAX31850) GetTemperature() (*ThermocoupleReading, error) {
+	m.bus.lock()
+	defer m.bus.unlock()
+
+	if err := m.bus.matchROM(m.rom); err != nil {
+		return nil, err
+	}
+	if err := m.bus.writeByte(0x44); err != nil {
+		return nil, err
+	}
+	if err := m.bus.wait(m.tConv, m.parasiticMode); err != nil {
+		return nil, err
+	}
+	return m.readScratchpad()
+}
+
+// Read temperature from scratchpad without measuring.
+// Check reading's Fault flag before using thermocouple temperature.
+func (m *MAX31850) ReadTemperature() (*ThermocoupleReading, error) {
+	m.bus.lock()
+	defer m.bus.unlock()
+
+	return m.readScratchpad()
+}
+
+func (m *MAX31850) readScratchpad() (*ThermocoupleReading, error) {
+	if err := m.bus.matchROM(m.rom); err != nil {
+		return nil, err
+	}
+	data, err := m.bus.readScratchpad()
+	if err != nil {
+		return nil, err
+	}
+	return decodeMAX31850(data), nil
+}
+
+// The scratchpad of MAX31850:
+//
+//	byte 0, 1 - thermocouple temperature (14 bits, 0.25ºC) and fault bit 0
+//	byte 2, 3 - cold-junction temperature (12 bits, 0.0625ºC) and SCV, SCG, OC bits 2..0
+//	byte 4    - configuration: 1111 AD3 AD2 AD1 AD0
+//	byte 5..7 - reserved
+func decodeMAX31850(data []byte) *ThermocoupleReading {
+	tc := int16(binary.LittleEndian.Uint16(data[0:2]))
+	cj := int16(binary.LittleEndian.Uint16(data[2:4]))
+	return &ThermocoupleReading{
+		Thermocouple: Temperature(tc>>2) * temperatureScale / 4,
+		ColdJunction: Temperature(cj>>4) * temperatureScale / 16,
+		Fault:        data[0]&0b1 != 0,
+		OpenCircuit:  data[2]&0b001 != 0,
+		ShortToGND:   data[2]&0b010 != 0,
+		ShortToVDD:   data[2]&0b100 != 0,
+		AddressPins:  data[4] & 0x0f,
+	}
+}
+
+func isMAX31850(scratchpad []byte) bool {
+	return scratchpad[4]&0xf0 == 0xf0
+}
+
+// Read state of hardware address pins AD3..AD0 (DS1825 only).
+func (s *TemperatureSensor) ReadAddressPins() (byte, error) {
+	if s.familyCode != FamilyDS1825 {
+		return 0, errors.New("address pins are supported by DS1825 only")
+	}
+
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	if sp, err := s.readDecodedScratchpad(); err != nil {
+		return 0, err
+	} else {
+		return sp.Config.AddressPins(), nil
+	}
+}
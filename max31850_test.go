@@ -0,0 +1,50 @@
+package digitemp
+
+import "testing"
+
+func TestDecodeMAX31850(t *testing.T) {
+	r := decodeMAX31850([]byte{0x90, 0x01, 0x10, 0x19, 0xf5, 0xff, 0xff, 0xff})
+	if r.Thermocouple != 250000 || r.ColdJunction != 250625 || r.Fault || r.OpenCircuit {
+		t.Errorf("got: %+v", r)
+	}
+	if r.AddressPins != 0x5 {
+		t.Errorf("address pins: %d", r.AddressPins)
+	}
+
+	r = decodeMAX31850([]byte{0xfd, 0xff, 0xf1, 0xff, 0xf0, 0xff, 0xff, 0xff})
+	if r.Thermocouple != -2500 || r.ColdJunction != -625 {
+		t.Errorf("got: %s, %s", r.Thermocouple, r.ColdJunction)
+	}
+	if !r.Fault || !r.OpenCircuit || r.ShortToGND || r.ShortToVDD {
+		t.Errorf("got: %+v", r)
+	}
+}
+
+func TestIsMAX31850(t *testing.T) {
+	if !isMAX31850([]byte{0, 0, 0, 0, 0xf3, 0xff, 0xff, 0xff}) {
+		t.Error("MAX31850 not detected")
+	}
+	if isMAX31850([]byte{0, 0, 0, 0, 0x73, 0xff, 0xff, 0xff}) { // DS1825, 12 bits
+		t.Error("DS1825 detected as MAX31850")
+	}
+}
+
+func TestTemperatureSensor_DS1825Configure(t *testing.T) {
+	device := newFakeThermometer("3B112233445566EA")
+	device.configFixed = 0x10 | 0x05 // AD3..AD0 = 0101
+	device.scratchpad[4] = 0x70 | 0x05
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+	if err := sensor.Configure(40, -10, Resolution10bits, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sensor.SetResolution(Resolution11bits); err != nil {
+		t.Fatal(err)
+	}
+	if pins, err := sensor.ReadAddressPins(); err != nil || pins != 0x05 {
+		t.Errorf("got: 0x%X, %v", pins, err)
+	}
+	if sensor.GetResolution() != Resolution11bits || device.scratchpad[4] != 0x55 {
+		t.Errorf("resolution: %d, config: 0x%02X", sensor.GetResolution(), device.scratchpad[4])
+	}
+}
@@ -0,0 +1,123 @@
+package digitemp
+
+import (
+	"time"
+)
+
+// Number of times scratchpad reading is retried on failure (e.g. CRC error) while taking a measurement.
+const measurementRetries = 2
+
+// Temperature measurement along with everything known about it at the time it was taken.
+type Measurement struct {
+	ROM            *ROM
+	FamilyCode     byte
+	Name           string
+	StartedAt      time.Time   // time the conversion was initiated at
+	FinishedAt     time.Time   // time the conversion was finished at
+	Resolution     byte        // resolution at read time
+	ParasiticMode  bool        // power mode of the sensor
+	Scratchpad     [9]byte     // raw scratchpad including CRC
+	Temperature    Temperature // calibrated, valid only if Err is nil
+	RawTemperature Temperature // not calibrated, valid only if Err is nil
+	Retries        int         // number of scratchpad read retries and re-triggered conversions
+	Err            error
+}
+
+// Measure temperature and read it from scratchpad.
+// Returned measurement is never nil and carries the error as well.
+func (s *TemperatureSensor) Measure() (*Measurement, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	m := s.newMeasurement()
+	m.StartedAt = time.Now()
+	if err := s.convertT(); err != nil {
+		m.Err = err
+		return m, err
+	}
+	m.FinishedAt = time.Now()
+	s.readMeasurement(m)
+	return m, m.Err
+}
+
+// Read temperature from scratchpad without measuring.
+// Returned measurement is never nil and carries the error as well.
+func (s *TemperatureSensor) ReadMeasurement() (*Measurement, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	m := s.newMeasurement()
+	s.readMeasurement(m)
+	return m, m.Err
+}
+
+// Initiate a single temperature conversion for all connected temperature sensors at once
+// and read measurements from all sensors created on this bus.
+// Returns error only if the conversion failed. Errors of individual sensors are reported in measurements.
+func (a *UARTAdapter) MeasureAll() ([]*Measurement, error) {
+	a.lock()
+	defer a.unlock()
+
+	startedAt := time.Now()
+	tConv, err := a.convertAll()
+	if err != nil {
+		return nil, err
+	}
+	finishedAt := startedAt.Add(tConv)
+
+	measurements := make([]*Measurement, 0, len(a.sensors))
+	for _, s := range a.sensors {
+		m := s.newMeasurement()
+		m.StartedAt = startedAt
+		m.FinishedAt = finishedAt
+		s.readMeasurement(m)
+		measurements = append(measurements, m)
+	}
+	return measurements, nil
+}
+
+func (s *TemperatureSensor) newMeasurement() *Measurement {
+	return &Measurement{
+		ROM:           s.rom,
+		FamilyCode:    s.familyCode,
+		Name:          s.description,
+		Resolution:    s.resolution,
+		ParasiticMode: s.parasiticMode,
+	}
+}
+
+// Read scratchpad (retrying on failure) and fill the measurement with its data.
+// Re-triggers the conversion if the reading is not valid and validation policy says so.
+func (s *TemperatureSensor) readMeasurement(m *Measurement) {
+	var data []byte
+	for conversions := 0; ; conversions++ {
+		for reads := 0; ; reads++ {
+			data, m.Err = s.readScratchpadRaw()
+			if m.Err == nil || reads >= measurementRetries {
+				break
+			}
+			m.Retries++
+		}
+		if m.Err == nil {
+			m.Err = s.validateScratchpad(data[0:8])
+		}
+		if !IsValidationError(m.Err) || s.validation != ValidateRetry || conversions >= s.validationRetries {
+			break
+		}
+		m.Retries++
+		if err := s.convertT(); err != nil {
+			m.Err = err
+			return
+		}
+		m.FinishedAt = time.Now()
+	}
+	copy(m.Scratchpad[0:9], data)
+	if m.Err != nil {
+		return
+	}
+	if hasConfigRegister(s.familyCode) {
+		m.Resolution = ConfigRegister(data[4]).Resolution()
+	}
+	m.RawTemperature = Temperature(s.calcTemperature(data[0:8]))
+	m.Temperature = s.calibration.Apply(m.RawTemperature)
+}
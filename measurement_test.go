@@ -0,0 +1,60 @@
+package digitemp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemperatureSensor_readMeasurement(t *testing.T) {
+	device := newFakeThermometer("2811223344556656")
+	device.raw = 0x0191
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+	m := sensor.newMeasurement()
+	if err := sensor.convertT(); err != nil {
+		t.Fatal(err)
+	}
+	sensor.readMeasurement(m)
+	if m.Err != nil || m.Temperature != 250625 || m.Retries != 0 {
+		t.Errorf("got: %v, %v, retries: %d", m.Temperature, m.Err, m.Retries)
+	}
+}
+
+func TestTemperatureSensor_readMeasurementBogus(t *testing.T) {
+	// broken bus: CRC retries don't help
+	device := newFakeThermometer("2811223344556656")
+	device.bogusReads = measurementRetries + 1
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+	m := sensor.newMeasurement()
+	sensor.readMeasurement(m)
+	if !errors.Is(m.Err, ErrBogusReading) {
+		t.Errorf("got: %v, expected: %v", m.Err, ErrBogusReading)
+	}
+
+	// bus recovers after re-conversion
+	device.bogusReads = measurementRetries + 1
+	sensor.SetValidationPolicy(ValidateRetry, 1)
+	m = sensor.newMeasurement()
+	sensor.readMeasurement(m)
+	if m.Err != nil || m.Temperature != 250625 {
+		t.Errorf("got: %v, %v", m.Temperature, m.Err)
+	}
+	if device.conversions != 1 || m.Retries != measurementRetries+1 {
+		t.Errorf("conversions: %d, retries: %d", device.conversions, m.Retries)
+	}
+}
+
+func TestTemperatureSensor_readMeasurementZeros(t *testing.T) {
+	// bus held low: all-zero scratchpad has valid CRC
+	device := newFakeThermometer("10112233445566B3")
+	device.zeroReads = 1
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+	sensor.updateResolution(ResolutionExtended)
+
+	m := sensor.newMeasurement()
+	sensor.readMeasurement(m)
+	if !errors.Is(m.Err, ErrBogusReading) {
+		t.Errorf("got: %v, expected: %v", m.Err, ErrBogusReading)
+	}
+}
@@ -0,0 +1,75 @@
+package digitemp
+
+import (
+	"fmt"
+)
+
+// Status of two PIO channels as returned by PIO ACCESS READ of DS2413 and DS28EA00.
+//
+//	bit 0 - PIOA pin state
+//	bit 1 - PIOA output latch state
+//	bit 2 - PIOB pin state
+//	bit 3 - PIOB output latch state
+type PIOStatus byte
+
+// Channels of two-channel PIO devices.
+const (
+	PIOA = 0
+	PIOB = 1
+)
+
+// Get sensed logic level of the channel's pin.
+func (p PIOStatus) Pin(channel int) bool {
+	return (p>>(channel*2))&0b1 != 0
+}
+
+// Get output latch state of the channel. False means the output transistor is on and pulls the pin low.
+func (p PIOStatus) Latch(channel int) bool {
+	return (p>>(channel*2+1))&0b1 != 0
+}
+
+// Read PIO status byte.
+func (a *UARTAdapter) readPIOStatus() (PIOStatus, error) {
+	b, err := a.readByte()
+	if err != nil {
+		return 0, err
+	}
+	return decodePIOStatus(b)
+}
+
+// Decode PIO status byte. Its upper nibble shall be complement of the lower one.
+func decodePIOStatus(b byte) (PIOStatus, error) {
+	if b>>4 != ^b&0x0f {
+		return 0, fmt.Errorf("pio status check failed: 0x%02X", b)
+	}
+	return PIOStatus(b & 0x0f), nil
+}
+
+// Encode output latches of two-channel PIO for PIO ACCESS WRITE. Unused upper bits are written as 1.
+func encodePIOLatches(pioA bool, pioB bool) byte {
+	var data byte = 0b11111100
+	if pioA {
+		data |= 0b01
+	}
+	if pioB {
+		data |= 0b10
+	}
+	return data
+}
+
+// Write byte followed by its complement and check the device confirms it with AAh.
+// It's how PIO ACCESS WRITE and similar commands protect data from bus errors.
+func (a *UARTAdapter) writeConfirmedByte(data byte) error {
+	if err := a.writeByte(data); err != nil {
+		return err
+	}
+	if err := a.writeByte(^data); err != nil {
+		return err
+	}
+	if b, err := a.readByte(); err != nil {
+		return err
+	} else if b != 0xaa {
+		return fmt.Errorf("write not confirmed (got: 0x%02X)", b)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package digitemp
+
+import "testing"
+
+func TestPIOStatus(t *testing.T) {
+	// PIOA: pin low, latch on; PIOB: pin high, latch off
+	status := PIOStatus(0b1100)
+	if status.Pin(PIOA) || status.Latch(PIOA) {
+		t.Errorf("PIOA: pin %t, latch %t", status.Pin(PIOA), status.Latch(PIOA))
+	}
+	if !status.Pin(PIOB) || !status.Latch(PIOB) {
+		t.Errorf("PIOB: pin %t, latch %t", status.Pin(PIOB), status.Latch(PIOB))
+	}
+}
+
+func TestSwitchInterface(t *testing.T) {
+	var _ Switch = (*DS2413)(nil)
+	var _ Switch = (*DS2408)(nil)
+	var _ Switch = (*DS2406)(nil)
+	var _ Switch = (*DS2405)(nil)
+}
@@ -1,6 +1,7 @@
 package digitemp
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strconv"
 	"strings"
@@ -31,6 +32,58 @@ func NewROMFromString(code string) (*ROM, error) {
 	return r, nil
 }
 
+// Parse ROM code in any of supported notations:
+//
+//	10A75CA80208001A, 10:A7:5C:A8:02:08:00:1A - bytes in wire order (digitemp), optionally separated by ':', '-' or ' '
+//	10-000802a85ca7                           - Linux w1 sysfs name: family and 48-bit serial number
+//	10.A75CA8020800, 10.A75CA8020800.1A       - OWFS name: family, serial in wire order and optional CRC
+//
+// Notations are case insensitive. If CRC is not part of the notation it is calculated.
+// If verifyCRC is true, CRC given in the notation must match the calculated one.
+func ParseROM(code string, verifyCRC bool) (*ROM, error) {
+	var hex string
+	var hasCRC bool
+	if parts := strings.Split(code, "."); len(parts) > 1 {
+		// OWFS
+		if len(parts) > 3 || len(parts[0]) != 2 || len(parts[1]) != 12 || (len(parts) == 3 && len(parts[2]) != 2) {
+			return nil, fmt.Errorf("wrong OWFS rom code format: %s", code)
+		}
+		hex = strings.Join(parts, "")
+		hasCRC = len(parts) == 3
+	} else if parts := strings.Split(code, "-"); len(parts) == 2 {
+		// Linux w1
+		if len(parts[0]) != 2 || len(parts[1]) != 12 {
+			return nil, fmt.Errorf("wrong w1 rom code format: %s", code)
+		}
+		serial := parts[1]
+		hex = parts[0]
+		for n := 10; n >= 0; n -= 2 {
+			hex += serial[n : n+2]
+		}
+	} else {
+		hex = strings.NewReplacer(":", "", "-", "", " ", "").Replace(code)
+		if len(hex) != 14 && len(hex) != 16 {
+			return nil, fmt.Errorf("wrong rom code length: %s", code)
+		}
+		hasCRC = len(hex) == 16
+	}
+
+	r := new(ROM)
+	for i := 0; i < len(hex)/2; i += 1 {
+		if b, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8); err != nil {
+			return nil, fmt.Errorf("wrong rom code: %s", code)
+		} else {
+			r.Code[i] = byte(b)
+		}
+	}
+	if !hasCRC {
+		r.Code[7] = crc8(r.Code[0:7])
+	} else if verifyCRC && !r.IsValid() {
+		return nil, fmt.Errorf("rom code crc error: %s", code)
+	}
+	return r, nil
+}
+
 func (r *ROM) String() string {
 	var bytes = make([]string, 8)
 	for _, b := range r.Code {
@@ -39,6 +92,81 @@ func (r *ROM) String() string {
 	return strings.Join(bytes, "")
 }
 
+// Format ROM code as Linux w1 sysfs name, e.g. 10-000802a85ca7.
+func (r *ROM) FormatW1() string {
+	return fmt.Sprintf("%02x-%012x", r.Family(), r.Serial())
+}
+
+// Format ROM code as OWFS name, e.g. 10.A75CA8020800 or 10.A75CA8020800.1A with CRC.
+func (r *ROM) FormatOWFS(withCRC bool) string {
+	name := fmt.Sprintf("%02X.%X", r.Code[0], r.Code[1:7])
+	if withCRC {
+		name += fmt.Sprintf(".%02X", r.Code[7])
+	}
+	return name
+}
+
+// Encodes ROM code as 16 hex digits in wire order.
+// It makes ROM usable in JSON and other text based formats.
+func (r ROM) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Decodes ROM code in any notation supported by ParseROM.
+func (r *ROM) UnmarshalText(text []byte) error {
+	if rom, err := ParseROM(string(text), true); err != nil {
+		return err
+	} else {
+		r.Code = rom.Code
+		return nil
+	}
+}
+
+// Implements sql.Scanner. Accepts 8 raw bytes or text in any notation supported by ParseROM.
+func (r *ROM) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return r.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == 8 {
+			copy(r.Code[0:8], v)
+			return nil
+		}
+		return r.UnmarshalText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ROM", src)
+	}
+}
+
+// Implements driver.Valuer. ROM code is stored as 16 hex digits in wire order.
+func (r ROM) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Get family code of the device.
+func (r *ROM) Family() byte {
+	return r.Code[0]
+}
+
+// Get 48-bit serial number of the device.
+func (r *ROM) Serial() uint64 {
+	var serial uint64
+	for n := 6; n > 0; n-- {
+		serial = serial<<8 | uint64(r.Code[n])
+	}
+	return serial
+}
+
+// Get CRC of the ROM code.
+func (r *ROM) CRC() byte {
+	return r.Code[7]
+}
+
+// Get description of the device family. Returns nil if the family is unknown.
+func (r *ROM) FamilyInfo() *FamilyInfo {
+	return LookupFamily(r.Family())
+}
+
 func (r *ROM) IsValid() bool {
 	return crc8(r.Code[0:7]) == r.Code[7]
 }
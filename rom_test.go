@@ -1,6 +1,9 @@
 package digitemp
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestNewROMFromBytes(t *testing.T) {
 	bytes := []byte{0x28, 0x25, 0xea, 0x52, 0x05, 0x10, 0xf3, 0xce}
@@ -32,3 +35,103 @@ func TestROM_toBits(t *testing.T) {
 		t.Errorf("%s != %s", newRom.String(), str)
 	}
 }
+
+func TestROM_Accessors(t *testing.T) {
+	rom, err := NewROMFromString("2825EA520510F3CE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rom.Family() != 0x28 {
+		t.Errorf("family: 0x%02X", rom.Family())
+	}
+	if rom.Serial() != 0xf3100552ea25 {
+		t.Errorf("serial: 0x%012X", rom.Serial())
+	}
+	if rom.CRC() != 0xce {
+		t.Errorf("crc: 0x%02X", rom.CRC())
+	}
+	if f := rom.FamilyInfo(); f == nil || f.Parts[0] != "DS18B20" || f.Category != CategoryTemperature {
+		t.Errorf("family info: %v", f)
+	}
+	if f := LookupFamily(0x3a); f == nil || f.String() != "DS2413 - Dual Channel Addressable Switch" {
+		t.Errorf("family info: %v", f)
+	}
+	if f := LookupFamily(0xfe); f != nil {
+		t.Errorf("unknown family found: %v", f)
+	}
+}
+
+func TestParseROM(t *testing.T) {
+	str := "10A75CA80208001A"
+	var testcases = []string{
+		"10A75CA80208001A",
+		"10a75ca80208001a",
+		"10:a7:5c:a8:02:08:00:1a",
+		"10-A7-5C-A8-02-08-00-1A",
+		"10A75CA8020800",
+		"10-000802a85ca7",
+		"10.A75CA8020800",
+		"10.A75CA8020800.1A",
+	}
+	for n, tc := range testcases {
+		if rom, err := ParseROM(tc, true); err != nil {
+			t.Errorf("(%d, %s: %v)", n, tc, err)
+		} else if rom.String() != str {
+			t.Errorf("(%d, got: %s, expected: %s)", n, rom, str)
+		}
+	}
+	for n, tc := range []string{"10A75CA80208001B", "10.A75CA8020800.1B", "10-000802a85ca", "10.A75CA8020800.1A.00", "10A75CA8020800XX"} {
+		if _, err := ParseROM(tc, true); err == nil {
+			t.Errorf("(%d, %s: no error)", n, tc)
+		}
+	}
+	if _, err := ParseROM("10A75CA80208001B", false); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestROM_Format(t *testing.T) {
+	rom := NewROMFromBytes([]byte{0x10, 0xa7, 0x5c, 0xa8, 0x02, 0x08, 0x00, 0x1a})
+	if s := rom.FormatW1(); s != "10-000802a85ca7" {
+		t.Errorf("w1: %s", s)
+	}
+	if s := rom.FormatOWFS(true); s != "10.A75CA8020800.1A" {
+		t.Errorf("owfs: %s", s)
+	}
+	if s := rom.FormatOWFS(false); s != "10.A75CA8020800" {
+		t.Errorf("owfs: %s", s)
+	}
+}
+
+func TestROM_MarshalJSON(t *testing.T) {
+	type config struct {
+		ROM ROM `json:"rom"`
+	}
+	data, err := json.Marshal(config{ROM: *NewROMFromBytes([]byte{0x10, 0xa7, 0x5c, 0xa8, 0x02, 0x08, 0x00, 0x1a})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"rom":"10A75CA80208001A"}` {
+		t.Errorf("got: %s", data)
+	}
+	var c config
+	if err := json.Unmarshal([]byte(`{"rom":"10-000802a85ca7"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.ROM.String() != "10A75CA80208001A" {
+		t.Errorf("got: %s", c.ROM.String())
+	}
+}
+
+func TestROM_Scan(t *testing.T) {
+	var rom ROM
+	if err := rom.Scan([]byte{0x10, 0xa7, 0x5c, 0xa8, 0x02, 0x08, 0x00, 0x1a}); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := rom.Value(); v != "10A75CA80208001A" {
+		t.Errorf("got: %v", v)
+	}
+	if err := rom.Scan(42); err == nil {
+		t.Error("scanned int")
+	}
+}
@@ -0,0 +1,125 @@
+package digitemp
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Configuration register of DS18B20, DS1822, DS1825 and DS28EA00.
+type ConfigRegister byte
+
+// Create configuration register value for the resolution.
+func NewConfigRegister(resolution byte) ConfigRegister {
+	return ConfigRegister((resolution&0b11)<<5 | 0b00011111)
+}
+
+// Change resolution keeping the rest of the register, e.g. DS1825 address pins.
+func (c ConfigRegister) WithResolution(resolution byte) ConfigRegister {
+	return c&^0b01100000 | ConfigRegister(resolution&0b11)<<5
+}
+
+// Get resolution (R1 and R0 bits).
+func (c ConfigRegister) Resolution() byte {
+	return (byte(c) >> 5) & 0b11
+}
+
+// Get state of address pins AD3..AD0 (DS1825 only).
+func (c ConfigRegister) AddressPins() byte {
+	return byte(c) & 0x0f
+}
+
+// Decoded scratchpad of a temperature sensor.
+//
+//	byte | DS18B20, DS1822,  | DS18S20
+//	     | DS1825, DS28EA00  |
+//	-----+-------------------+-------------
+//	0, 1 | temperature       | temperature
+//	2    | TH                | TH
+//	3    | TL                | TL
+//	4    | config register   | reserved
+//	5    | reserved          | reserved
+//	6    | reserved          | COUNT_REMAIN
+//	7    | reserved          | COUNT_PER_C
+//	8    | CRC               | CRC
+type Scratchpad struct {
+	FamilyCode  byte
+	Temperature int16          // raw temperature register
+	High        int8           // TH register or user byte 1
+	Low         int8           // TL register or user byte 2
+	Config      ConfigRegister // DS18B20, DS1822, DS1825, DS28EA00 only
+	Reserved    []byte         // reserved bytes in the order they appear in the scratchpad
+	CountRemain byte           // DS18S20 only
+	CountPerC   byte           // DS18S20 only
+	CRC         byte
+}
+
+// Decode scratchpad of the device family. Data is 8 bytes of the scratchpad or 9 bytes including CRC.
+// CRC is verified if given.
+func ParseScratchpad(familyCode byte, data []byte) (*Scratchpad, error) {
+	switch len(data) {
+	case 8:
+	case 9:
+		if crc8(data[0:8]) != data[8] {
+			return nil, fmt.Errorf("scratchpad crc error")
+		}
+	default:
+		return nil, fmt.Errorf("wrong scratchpad length: %d", len(data))
+	}
+
+	sp := &Scratchpad{
+		FamilyCode:  familyCode,
+		Temperature: int16(binary.LittleEndian.Uint16(data[0:2])),
+		High:        int8(data[2]),
+		Low:         int8(data[3]),
+		CRC:         crc8(data[0:8]),
+	}
+	if hasCountRegisters(familyCode) {
+		sp.Reserved = []byte{data[4], data[5]}
+		sp.CountRemain = data[6]
+		sp.CountPerC = data[7]
+	} else {
+		sp.Config = ConfigRegister(data[4])
+		sp.Reserved = []byte{data[5], data[6], data[7]}
+	}
+	return sp, nil
+}
+
+// Encode scratchpad into 9 bytes including CRC.
+func (sp *Scratchpad) Bytes() []byte {
+	data := make([]byte, 9)
+	binary.LittleEndian.PutUint16(data[0:2], uint16(sp.Temperature))
+	data[2] = byte(sp.High)
+	data[3] = byte(sp.Low)
+	if hasCountRegisters(sp.FamilyCode) {
+		copy(data[4:6], sp.Reserved)
+		data[6] = sp.CountRemain
+		data[7] = sp.CountPerC
+	} else {
+		data[4] = byte(sp.Config)
+		copy(data[5:8], sp.Reserved)
+	}
+	data[8] = crc8(data[0:8])
+	return data
+}
+
+// Compare settings written to the scratchpad. Only R1 and R0 bits of config register are writable,
+// the rest are fixed or reflect hardware, like DS1825 address pins.
+func (sp *Scratchpad) sameSettings(other *Scratchpad) bool {
+	if sp.High != other.High || sp.Low != other.Low {
+		return false
+	}
+	if hasConfigRegister(sp.FamilyCode) {
+		return sp.Config.Resolution() == other.Config.Resolution()
+	}
+	return true
+}
+
+// Get bytes to be written by WRITE SCRATCHPAD command: TH, TL and config register if the device has one.
+func (sp *Scratchpad) settings() []byte {
+	data := make([]byte, 0, 3)
+	data = append(data, byte(sp.High), byte(sp.Low))
+	if hasConfigRegister(sp.FamilyCode) {
+		data = append(data, byte(sp.Config))
+	}
+	return data
+}
@@ -0,0 +1,61 @@
+package digitemp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseScratchpad_DS18B20(t *testing.T) {
+	data := []byte{0x91, 0x01, 0x4b, 0x46, 0x7f, 0xff, 0x0f, 0x10, 0x00}
+	data[8] = crc8(data[0:8])
+	sp, err := ParseScratchpad(FamilyDS18B20, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.Temperature != 0x0191 || sp.High != 75 || sp.Low != 70 {
+		t.Errorf("got: %+v", sp)
+	}
+	if sp.Config.Resolution() != Resolution12bits {
+		t.Errorf("resolution: %d", sp.Config.Resolution())
+	}
+	if !bytes.Equal(sp.Reserved, []byte{0xff, 0x0f, 0x10}) {
+		t.Errorf("reserved: % X", sp.Reserved)
+	}
+	if !bytes.Equal(sp.Bytes(), data) {
+		t.Errorf("encoded: % X, expected: % X", sp.Bytes(), data)
+	}
+	if NewConfigRegister(Resolution9bits) != 0x1f {
+		t.Errorf("config: 0x%02X", NewConfigRegister(Resolution9bits))
+	}
+
+	data[8] ^= 0xff
+	if _, err := ParseScratchpad(FamilyDS18B20, data); err == nil {
+		t.Error("crc error not detected")
+	}
+}
+
+func TestParseScratchpad_DS18S20(t *testing.T) {
+	data := []byte{0x32, 0x00, 0x4b, 0x46, 0xff, 0xff, 0x0c, 0x10}
+	sp, err := ParseScratchpad(FamilyDS18S20, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.Temperature != 0x32 || sp.CountRemain != 0x0c || sp.CountPerC != 0x10 {
+		t.Errorf("got: %+v", sp)
+	}
+	if !bytes.Equal(sp.Bytes()[0:8], data) {
+		t.Errorf("encoded: % X, expected: % X", sp.Bytes(), data)
+	}
+	if !bytes.Equal(sp.settings(), []byte{0x4b, 0x46}) {
+		t.Errorf("settings: % X", sp.settings())
+	}
+}
+
+func TestConfigRegister_WithResolution(t *testing.T) {
+	if c := ConfigRegister(0x75).WithResolution(Resolution9bits); c != 0x15 || c.AddressPins() != 0x05 {
+		t.Errorf("got: 0x%02X", byte(c))
+	}
+	if c := NewConfigRegister(Resolution9bits).WithResolution(Resolution12bits); c != 0x7f {
+		t.Errorf("got: 0x%02X", byte(c))
+	}
+}
@@ -0,0 +1,38 @@
+package digitemp
+
+import (
+	"fmt"
+)
+
+// State of a switch channel.
+type ChannelState struct {
+	Pin   bool // sensed logic level of the pin
+	Latch bool // output latch, false means the output transistor is on and pulls the pin low
+}
+
+// Addressable switch with one or more PIO channels.
+type Switch interface {
+	Device
+
+	// Get number of PIO channels.
+	GetChannelCount() int
+
+	// Read state of all channels.
+	ReadChannels() ([]ChannelState, error)
+
+	// Read state of the channel.
+	ReadChannel(channel int) (ChannelState, error)
+
+	// Set output latch of the channel and verify the device latched it.
+	SetChannel(channel int, latch bool) error
+
+	// Invert output latch of the channel. Returns the new latch state.
+	ToggleChannel(channel int) (bool, error)
+}
+
+func checkChannel(channel int, count int) error {
+	if channel < 0 || channel >= count {
+		return fmt.Errorf("wrong channel: %d", channel)
+	}
+	return nil
+}
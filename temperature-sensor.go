@@ -1,13 +1,20 @@
 package digitemp
 
 import (
-	"bytes"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"time"
 )
 
+// Family codes of supported temperature sensors.
+const (
+	FamilyDS18S20  = 0x10
+	FamilyDS1822   = 0x22
+	FamilyDS18B20  = 0x28
+	FamilyDS1825   = 0x3b
+	FamilyDS28EA00 = 0x42
+)
+
 const (
 	Resolution9bits    = 0x0
 	Resolution10bits   = 0x1
@@ -27,6 +34,10 @@ type TemperatureSensor struct {
 	precision     string
 	tConv         time.Duration // temperature conversion time
 	tRW           time.Duration // eeprom write time
+
+	validation        ValidationPolicy
+	validationRetries int
+	calibration       *Calibration
 }
 
 //
@@ -42,6 +53,9 @@ func NewTemperatureSensor(bus *UARTAdapter, rom *ROM, required bool) (*Temperatu
 		resolution: Resolution9bits,
 		tConv:      750 * time.Millisecond,
 		tRW:        10 * time.Millisecond,
+
+		validation:        ValidateReport,
+		validationRetries: 1,
 	}
 
 	s.bus.lock()
@@ -74,38 +88,53 @@ func NewTemperatureSensor(bus *UARTAdapter, rom *ROM, required bool) (*Temperatu
 	}
 	s.familyCode = s.rom.Code[0]
 
-	switch s.familyCode {
-	case 0x00:
+	if d := LookupDriver(s.familyCode); d != nil {
+		s.description = d.Description
+	} else {
 		s.description = "Unidentified device"
-	case 0x10:
-		s.description = "DS18S20 - High-precision Digital Thermometer"
-	case 0x22:
-		s.description = "DS1822 - Econo Digital Thermometer"
-	case 0x28:
-		s.description = "DS18B20 - Programmable Resolution Digital Thermometer"
 	}
 
-	switch s.familyCode {
-	case 0x10:
-		if s.resolution == Resolution9bits {
-			s.precision = "9 bits"
-		} else {
-			s.precision = "extended"
-		}
-	case 0x22, 0x28:
-		if sp, err := s.readScratchpad(); err != nil {
+	if _, ok := thermometerLayout(s.familyCode); !ok {
+		s.precision = "unknown"
+	} else if hasConfigRegister(s.familyCode) {
+		if sp, err := s.readDecodedScratchpad(); err != nil {
 			return nil, err
 		} else {
-			s.resolution = (sp[4] >> 5) & 0b11
+			s.updateResolution(sp.Config.Resolution())
 		}
-		s.tConv = time.Millisecond * (750 / (8 >> s.resolution))
-		s.precision = fmt.Sprintf("%d bits", 9+s.resolution)
-	default:
-		s.precision = "unknown"
+	} else {
+		s.updateResolution(s.resolution)
 	}
+
+	s.bus.addSensor(s)
 	return s, nil
 }
 
+func init() {
+	mustRegisterDriver(&Driver{
+		Name:        "DS18S20",
+		Description: "DS18S20 - High-precision Digital Thermometer",
+		FamilyCodes: []byte{FamilyDS18S20},
+		New:         newTemperatureSensorDevice,
+	})
+	mustRegisterDriver(&Driver{
+		Name:        "DS1822",
+		Description: "DS1822 - Econo Digital Thermometer",
+		FamilyCodes: []byte{FamilyDS1822},
+		New:         newTemperatureSensorDevice,
+	})
+	mustRegisterDriver(&Driver{
+		Name:        "DS18B20",
+		Description: "DS18B20 - Programmable Resolution Digital Thermometer",
+		FamilyCodes: []byte{FamilyDS18B20},
+		New:         newTemperatureSensorDevice,
+	})
+}
+
+func newTemperatureSensorDevice(bus *UARTAdapter, rom *ROM) (Device, error) {
+	return NewTemperatureSensor(bus, rom, true)
+}
+
 func (s *TemperatureSensor) GetROM() *ROM {
 	return s.rom
 }
@@ -126,6 +155,20 @@ func (s *TemperatureSensor) IsParasiticMode() bool {
 	return s.parasiticMode
 }
 
+// Forget the sensor. It will not be measured by the bus anymore.
+func (s *TemperatureSensor) Close() error {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	for n, sensor := range s.bus.sensors {
+		if sensor == s {
+			s.bus.sensors = append(s.bus.sensors[:n], s.bus.sensors[n+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
 func (s *TemperatureSensor) SaveEEPROM() error {
 	s.bus.lock()
 	defer s.bus.unlock()
@@ -143,66 +186,97 @@ func (s *TemperatureSensor) LoadEEPROM() error {
 	if err := s.recallScratchpad(); err != nil {
 		return err
 	}
+	return s.syncResolution()
+}
+
+// Update resolution from the scratchpad after its settings were changed by the device, e.g. by recall.
+func (s *TemperatureSensor) syncResolution() error {
+	if !hasConfigRegister(s.familyCode) {
+		return nil
+	}
+	if sp, err := s.readDecodedScratchpad(); err != nil {
+		return err
+	} else {
+		s.updateResolution(sp.Config.Resolution())
+	}
 	return nil
 }
 
 // Measure temperature and read from scratchpad
 // Returns temperature * 100 in ºC as int
 func (s *TemperatureSensor) GetTemperature() (int, error) {
-	s.bus.lock()
-	defer s.bus.unlock()
-
-	if err := s.convertT(); err != nil {
+	if t, err := s.GetTemperatureValue(); err != nil {
 		return 0, err
+	} else {
+		return t.Centi(), nil
 	}
-	return s.readTemperature()
 }
 
 // Measure temperature and read from scratchpad
 // Return temperature in ºC as float
 func (s *TemperatureSensor) GetTemperatureFloat() (float32, error) {
-	if t, err := s.GetTemperature(); err != nil {
+	if t, err := s.GetTemperatureValue(); err != nil {
 		return 0, err
 	} else {
-		return float32(t) / 100.0, nil
+		return float32(t.Celsius()), nil
 	}
 }
 
-// Read temperature from scratchpad without measuring
-// Returns temperature * 100 in ºC as int
-func (s *TemperatureSensor) ReadTemperature() (int, error) {
+// Measure temperature and read from scratchpad
+// Returns temperature in full sensor's resolution
+func (s *TemperatureSensor) GetTemperatureValue() (Temperature, error) {
 	s.bus.lock()
 	defer s.bus.unlock()
 
+	if err := s.convertT(); err != nil {
+		return 0, err
+	}
 	return s.readTemperature()
 }
 
-func (s *TemperatureSensor) readTemperature() (int, error) {
-	if sp, err := s.readScratchpad(); err != nil {
+// Read temperature from scratchpad without measuring
+// Returns temperature * 100 in ºC as int
+func (s *TemperatureSensor) ReadTemperature() (int, error) {
+	if t, err := s.ReadTemperatureValue(); err != nil {
 		return 0, err
 	} else {
-		return s.calcTemperature(sp) / 100, nil
+		return t.Centi(), nil
 	}
 }
 
 // Read temperature from scratchpad without measuring
 // Returns temperature ºC as float
 func (s *TemperatureSensor) ReadTemperatureFloat() (float32, error) {
-	if t, err := s.ReadTemperature(); err != nil {
+	if t, err := s.ReadTemperatureValue(); err != nil {
 		return 0, err
 	} else {
-		return float32(t) / 100.0, nil
+		return float32(t.Celsius()), nil
 	}
 }
 
+// Read temperature from scratchpad without measuring
+// Returns temperature in full sensor's resolution
+func (s *TemperatureSensor) ReadTemperatureValue() (Temperature, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	return s.readTemperature()
+}
+
+func (s *TemperatureSensor) readTemperature() (Temperature, error) {
+	m := s.newMeasurement()
+	s.readMeasurement(m)
+	return m.Temperature, m.Err
+}
+
 func (s *TemperatureSensor) GetAlarms() (int8, int8, error) {
 	s.bus.lock()
 	defer s.bus.unlock()
 
-	if sp, err := s.readScratchpad(); err != nil {
+	if sp, err := s.readDecodedScratchpad(); err != nil {
 		return 0, 0, err
 	} else {
-		return int8(sp[2]), int8(sp[3]), nil
+		return sp.High, sp.Low, nil
 	}
 }
 
@@ -210,33 +284,94 @@ func (s *TemperatureSensor) SetAlarms(high int8, low int8) error {
 	s.bus.lock()
 	defer s.bus.unlock()
 
-	var scratchpad []byte
-	if sp, err := s.readScratchpad(); err != nil {
+	sp, err := s.readDecodedScratchpad()
+	if err != nil {
 		return err
-	} else {
-		scratchpad = sp
 	}
+	sp.High = high
+	sp.Low = low
+	return s.writeScratchpad(sp.settings())
+}
 
-	data := make([]byte, 0, 3)
-	data = append(data, byte(high), byte(low))
-	switch s.familyCode {
-	case 0x22, 0x28:
-		data = append(data, scratchpad[4])
+func (s *TemperatureSensor) GetResolution() byte {
+	return s.resolution
+}
+
+func (s *TemperatureSensor) SetResolution(resolution byte) error {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	if hasConfigRegister(s.familyCode) {
+		sp, err := s.readDecodedScratchpad()
+		if err != nil {
+			return err
+		}
+		sp.Config = sp.Config.WithResolution(resolution)
+		if err := s.writeScratchpad(sp.settings()); err != nil {
+			return err
+		}
 	}
+	s.updateResolution(resolution)
+	return nil
+}
+
+// Read and decode scratchpad.
+func (s *TemperatureSensor) ReadScratchpad() (*Scratchpad, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	return s.readDecodedScratchpad()
+}
 
-	if err := s.writeScratchpad(data); err != nil {
+// Write alarm thresholds and resolution, read them back to verify and, if persist is true,
+// copy them to EEPROM. All in one bus transaction.
+func (s *TemperatureSensor) Configure(high int8, low int8, resolution byte, persist bool) error {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	sp, err := s.readDecodedScratchpad()
+	if err != nil {
+		return err
+	}
+	sp.High = high
+	sp.Low = low
+	if hasConfigRegister(s.familyCode) {
+		sp.Config = sp.Config.WithResolution(resolution)
+	}
+	if err := s.writeVerifiedSettings(sp); err != nil {
 		return err
 	}
+	s.updateResolution(resolution)
+
+	if persist {
+		if err := s.copyScratchpad(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *TemperatureSensor) GetResolution() byte {
-	return s.resolution
+// Write TH, TL and config register from the scratchpad and read them back to verify.
+func (s *TemperatureSensor) writeVerifiedSettings(sp *Scratchpad) error {
+	if err := s.writeScratchpad(sp.settings()); err != nil {
+		return err
+	}
+	if readBack, err := s.readDecodedScratchpad(); err != nil {
+		return err
+	} else if !readBack.sameSettings(sp) {
+		return fmt.Errorf("scratchpad verification failed (wrote: % X, read: % X)", sp.settings(), readBack.settings())
+	}
+	return nil
 }
 
-func (s *TemperatureSensor) SetResolution(resolution byte) error {
-	switch s.familyCode {
-	case 0x10:
+// Update resolution, conversion time and precision description.
+func (s *TemperatureSensor) updateResolution(resolution byte) {
+	layout, ok := thermometerLayout(s.familyCode)
+	if !ok {
+		return
+	}
+	switch layout {
+	case LayoutDS18S20:
 		if resolution == Resolution9bits {
 			s.resolution = Resolution9bits
 			s.precision = "9 bits"
@@ -244,24 +379,11 @@ func (s *TemperatureSensor) SetResolution(resolution byte) error {
 			s.resolution = ResolutionExtended
 			s.precision = "extended"
 		}
-		return nil
-	case 0x22, 0x28:
-		var scratchpad []byte
-		var err error
-		if scratchpad, err = s.readScratchpad(); err != nil {
-			return err
-		}
+	case LayoutDS18B20:
 		s.resolution = resolution & 0b11
-		data := make([]byte, 0, 3)
-		data = append(data, scratchpad[2], scratchpad[3])
-		data = append(data, (s.resolution<<5)|0b00011111)
-		if err := s.writeScratchpad(data); err != nil {
-			return err
-		}
 		s.tConv = time.Millisecond * (750 / (8 >> s.resolution))
 		s.precision = fmt.Sprintf("%d bits", 9+s.resolution)
 	}
-	return nil
 }
 
 // CONVERT T [44h]
@@ -285,35 +407,25 @@ func (s *TemperatureSensor) inParasiticMode() (bool, error) {
 	if err := s.reset(); err != nil {
 		return false, err
 	}
-	if err := s.bus.writeByte(0xb4); err != nil {
-		return false, err
-	}
-	if pm, err := s.bus.readBit(); err != nil {
-		return false, err
-	} else {
-		return pm == 0b0, nil
-	}
+	return s.bus.readPowerSupply()
 }
 
 // READ SCRATCHPAD [BEh]
 // This command allows the bus driver to read the contents of the scratchpad.
-func (s *TemperatureSensor) readScratchpad() ([]byte, error) {
+// Reads all 9 bytes of the scratchpad including CRC.
+func (s *TemperatureSensor) readScratchpadRaw() ([]byte, error) {
 	if err := s.reset(); err != nil {
 		return nil, err
 	}
-	if err := s.bus.writeByte(0xbe); err != nil {
-		return nil, err
-	}
-	var data = make([]byte, 9)
-	if _, err := s.bus.readBytes(data); err != nil {
+	return s.bus.readScratchpad()
+}
+
+func (s *TemperatureSensor) readDecodedScratchpad() (*Scratchpad, error) {
+	if data, err := s.readScratchpadRaw(); err != nil {
 		return nil, err
+	} else {
+		return ParseScratchpad(s.familyCode, data)
 	}
-	scratchpad := data[0:8]
-	crc := data[8]
-	if crc8(scratchpad) != crc {
-		return nil, errors.New("scratchpad crc error")
-	}
-	return scratchpad, nil
 }
 
 // WRITE SCRATCHPAD [4Eh]
@@ -349,17 +461,15 @@ func (s *TemperatureSensor) copyScratchpad() error {
 
 // RECALL EE [B8h]
 // This command recalls values from EEPROM and places the data in the scratchpad memory.
+// The device can't signal completion in parasitic mode, so there it waits for EEPROM write time instead.
 func (s *TemperatureSensor) recallScratchpad() error {
-	if s.parasiticMode {
-		return nil
-	}
 	if err := s.reset(); err != nil {
 		return err
 	}
 	if err := s.bus.writeByte(0xb8); err != nil {
 		return err
 	}
-	if err := s.wait(s.tConv); err != nil {
+	if err := s.wait(s.tRW); err != nil {
 		return err
 	}
 	return nil
@@ -376,43 +486,11 @@ func (s *TemperatureSensor) reset() error {
 
 // Wait for specified time in parasitic mode or until operation is finished in external power mode.
 func (s *TemperatureSensor) wait(duration time.Duration) error {
-	if s.parasiticMode {
-		time.Sleep(duration)
-	} else {
-		startedAt := time.Now()
-		for {
-			if b, err := s.bus.readBit(); err != nil {
-				return err
-			} else {
-				if b != 0b0 {
-					break
-				}
-			}
-			if time.Since(startedAt) > duration {
-				break
-			}
-		}
-	}
-	return nil
+	return s.bus.wait(duration, s.parasiticMode)
 }
 
 // Read temperature value from the scratchpad
 // Returns temperature * 10000 ºC
 func (s *TemperatureSensor) calcTemperature(scratchpad []byte) int {
-	var t int16
-	_ = binary.Read(bytes.NewReader(scratchpad), binary.LittleEndian, &t)
-
-	var temp int
-	switch s.familyCode {
-	case 0x10:
-		temp = int(t) * 5000
-		if s.resolution > Resolution9bits {
-			countRemain := int(scratchpad[6])
-			countPerC := int(scratchpad[7])
-			temp = temp - 2500 + 10000*(countPerC-countRemain)/countPerC
-		}
-	case 0x22, 0x28:
-		temp = int(t) * 10000 / 16
-	}
-	return temp
+	return convertTemperature(s.familyCode, s.resolution, scratchpad)
 }
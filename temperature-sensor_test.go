@@ -2,6 +2,101 @@ package digitemp
 
 import "testing"
 
+// Fake DS18B20 family thermometer.
+type fakeThermometer struct {
+	*fakeDevice
+	scratchpad  [9]byte
+	eeprom      [3]byte // TH, TL, config
+	configFixed byte    // read-only bits of config register
+	parasitic   bool
+	raw         uint16 // temperature register after conversion
+	bogusReads  int    // number of reads returning all ones
+	zeroReads   int    // number of reads with the bus held low
+	conversions int
+	recalls     int
+	copyFails   bool // EEPROM is worn out
+	onConvert   func()
+}
+
+// Simulate power loss: settings are reloaded from EEPROM and temperature register is reset.
+func (t *fakeThermometer) powerOnReset() {
+	copy(t.scratchpad[2:5], t.eeprom[:])
+	t.scratchpad[0], t.scratchpad[1] = 0x50, 0x05
+	t.alarm = false
+}
+
+func newFakeThermometer(rom string) *fakeThermometer {
+	r, _ := NewROMFromString(rom)
+	t := &fakeThermometer{
+		fakeDevice:  &fakeDevice{rom: *r},
+		scratchpad:  [9]byte{0x50, 0x05, 0x4b, 0x46, 0x7f, 0xff, 0x0c, 0x10},
+		eeprom:      [3]byte{0x4b, 0x46, 0x7f},
+		configFixed: 0x1f,
+		raw:         0x0191, // 25.0625
+	}
+	t.onFunction = t.function
+	return t
+}
+
+func (t *fakeThermometer) function(d *fakeDevice, command byte) {
+	switch command {
+	case 0x44:
+		t.conversions++
+		t.scratchpad[0], t.scratchpad[1] = byte(t.raw), byte(t.raw>>8)
+		degrees := int8(int16(t.raw) >> 4)
+		d.alarm = degrees >= int8(t.scratchpad[2]) || degrees <= int8(t.scratchpad[3])
+		if t.onConvert != nil {
+			t.onConvert()
+		}
+	case 0xbe:
+		if t.bogusReads > 0 {
+			t.bogusReads--
+			d.send(0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+			return
+		}
+		if t.zeroReads > 0 {
+			t.zeroReads--
+			d.send(0, 0, 0, 0, 0, 0, 0, 0, 0)
+			return
+		}
+		t.scratchpad[8] = crc8(t.scratchpad[0:8])
+		d.send(t.scratchpad[:]...)
+	case 0x4e:
+		d.expect(3, func(data []byte) {
+			t.scratchpad[2], t.scratchpad[3] = data[0], data[1]
+			t.scratchpad[4] = data[2]&0x60 | t.configFixed
+		})
+	case 0x48:
+		if !t.copyFails {
+			copy(t.eeprom[:], t.scratchpad[2:5])
+		}
+	case 0xb8:
+		t.recalls++
+		copy(t.scratchpad[2:5], t.eeprom[:])
+	case 0xb4:
+		if t.parasitic {
+			d.tx = []byte{0}
+		} else {
+			d.tx = []byte{1}
+		}
+	}
+}
+
+func newFakeSensor(bus *UARTAdapter, t *fakeThermometer) *TemperatureSensor {
+	rom := t.rom
+	s := &TemperatureSensor{
+		bus:               bus,
+		rom:               &rom,
+		familyCode:        rom.Family(),
+		parasiticMode:     t.parasitic,
+		validation:        ValidateReport,
+		validationRetries: 1,
+	}
+	s.updateResolution(ConfigRegister(t.scratchpad[4]).Resolution())
+	s.tRW = 0
+	return s
+}
+
 type calcTemperatureTestcase struct {
 	scratchpad  []byte
 	temperature int
@@ -58,3 +153,81 @@ func TestTemperatureSensor_DS18S20_calcTemperature(t *testing.T) {
 		}
 	}
 }
+
+func TestTemperatureSensor_DS18S20_calcTemperatureExtended(t *testing.T) {
+	var sensor = TemperatureSensor{
+		familyCode: 0x10, // DS18S20
+		resolution: ResolutionExtended,
+	}
+	var testcases = []struct {
+		scratchpad  []byte
+		temperature int
+	}{
+		{[]byte{0x32, 0x00, 0x0c, 0x10}, 250000},  //  25.0
+		{[]byte{0x33, 0x00, 0x0c, 0x10}, 250000},  //  25.0, odd LSB
+		{[]byte{0x33, 0x00, 0x01, 0x10}, 256875},  //  25.6875
+		{[]byte{0x31, 0x00, 0x0f, 0x10}, 238125},  //  23.8125
+		{[]byte{0xff, 0xff, 0x0c, 0x10}, -10000},  //  -1.0, odd LSB
+		{[]byte{0xce, 0xff, 0x04, 0x10}, -245000}, // -24.5
+	}
+	var scrpad = [8]byte{0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x0C, 0x10}
+
+	for n, tc := range testcases {
+		scrpad[0], scrpad[1] = tc.scratchpad[0], tc.scratchpad[1]
+		scrpad[6], scrpad[7] = tc.scratchpad[2], tc.scratchpad[3]
+		if temp := sensor.calcTemperature(scrpad[:]); temp != tc.temperature {
+			t.Errorf("(%d, got: %d, expected: %d)", n, temp, tc.temperature)
+		}
+	}
+}
+
+func TestTemperatureSensor_validateScratchpad(t *testing.T) {
+	var testcases = []struct {
+		familyCode byte
+		scratchpad []byte
+		err        error
+	}{
+		{0x28, []byte{0x50, 0x05, 0x4b, 0x46, 0x7f, 0xff, 0x0c, 0x10}, ErrPowerOnReset},
+		{0x28, []byte{0x50, 0x05, 0x4b, 0x46, 0x7f, 0xff, 0x10, 0x10}, nil}, // real 85.0
+		{0x28, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, ErrBogusReading},
+		{0x28, []byte{0xff, 0xff, 0x4b, 0x46, 0x7f, 0xff, 0x01, 0x10}, nil}, // real -0.0625
+		{0x28, []byte{0xff, 0x07, 0x4b, 0x46, 0x7f, 0xff, 0x01, 0x10}, ErrOutOfRange},
+		{0x10, []byte{0xaa, 0x00, 0x4b, 0x46, 0xff, 0xff, 0x0c, 0x10}, ErrPowerOnReset},
+		{0x10, []byte{0x32, 0x00, 0x4b, 0x46, 0xff, 0xff, 0x0c, 0x10}, nil},
+		{0x10, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, ErrBogusReading},
+		{0x10, []byte{0x32, 0x00, 0x4b, 0x46, 0xff, 0xff, 0x0c, 0x00}, ErrBogusReading},
+		{0x28, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, ErrBogusReading},
+	}
+	for n, tc := range testcases {
+		sensor := TemperatureSensor{
+			familyCode: tc.familyCode,
+			resolution: ResolutionExtended,
+			validation: ValidateReport,
+		}
+		if err := sensor.validateScratchpad(tc.scratchpad); err != tc.err {
+			t.Errorf("(%d, got: %v, expected: %v)", n, err, tc.err)
+		}
+	}
+}
+
+func TestNewTemperatureSensor_Twice(t *testing.T) {
+	therm := newFakeThermometer("2811223344556656")
+	bus := newFakeBus(therm.fakeDevice)
+	rom := therm.rom
+
+	first, err := NewTemperatureSensor(bus, &rom, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewTemperatureSensor(bus, &rom, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.sensors) != 1 || bus.sensors[0] != second {
+		t.Errorf("got %d sensors, expected the second instance only", len(bus.sensors))
+	}
+	_ = first.Close()
+	if len(bus.sensors) != 1 {
+		t.Error("closing replaced instance removed the new one")
+	}
+}
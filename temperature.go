@@ -0,0 +1,77 @@
+package digitemp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Temperature in 1/10000 ºC.
+//
+// It keeps full resolution of supported sensors: 1/16 ºC steps of DS18B20/DS1822 as well as
+// DS18S20 extended resolution readings are stored without loss.
+type Temperature int32
+
+// Number of Temperature units in 1 ºC.
+const temperatureScale = 10000
+
+// Create temperature from ºC.
+func NewTemperatureFromCelsius(celsius float64) Temperature {
+	if celsius < 0 {
+		return Temperature(celsius*temperatureScale - 0.5)
+	}
+	return Temperature(celsius*temperatureScale + 0.5)
+}
+
+// Returns temperature in ºC.
+func (t Temperature) Celsius() float64 {
+	return float64(t) / temperatureScale
+}
+
+// Returns temperature in ºF.
+func (t Temperature) Fahrenheit() float64 {
+	return float64(int64(t)*9+32*5*temperatureScale) / (5 * temperatureScale)
+}
+
+// Returns temperature in K.
+func (t Temperature) Kelvin() float64 {
+	return float64(int64(t)+27315*temperatureScale/100) / temperatureScale
+}
+
+// Returns temperature * 100 in ºC as int. Same scale as `sensor.GetTemperature()` uses.
+func (t Temperature) Centi() int {
+	return int(t) / (temperatureScale / 100)
+}
+
+// Returns temperature * 1000 in ºC as int.
+func (t Temperature) Milli() int {
+	return int(t) / (temperatureScale / 1000)
+}
+
+func (t Temperature) String() string {
+	return t.decimal() + "ºC"
+}
+
+// Encodes temperature as a JSON number in ºC.
+func (t Temperature) MarshalJSON() ([]byte, error) {
+	return []byte(t.decimal()), nil
+}
+
+// Encodes temperature as a decimal number in ºC.
+func (t Temperature) MarshalText() ([]byte, error) {
+	return []byte(t.decimal()), nil
+}
+
+// Format temperature in ºC as an exact decimal number without trailing zeros.
+func (t Temperature) decimal() string {
+	v := int64(t)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%04d", v%temperatureScale), "0")
+	if fraction == "" {
+		fraction = "0"
+	}
+	return fmt.Sprintf("%s%d.%s", sign, v/temperatureScale, fraction)
+}
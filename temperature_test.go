@@ -0,0 +1,54 @@
+package digitemp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTemperature_String(t *testing.T) {
+	var testcases = []struct {
+		temperature Temperature
+		str         string
+	}{
+		{250625, "25.0625ºC"},
+		{-250625, "-25.0625ºC"},
+		{1250000, "125.0ºC"},
+		{5000, "0.5ºC"},
+		{-625, "-0.0625ºC"},
+		{0, "0.0ºC"},
+	}
+	for n, tc := range testcases {
+		if s := tc.temperature.String(); s != tc.str {
+			t.Errorf("(%d, got: %s, expected: %s)", n, s, tc.str)
+		}
+	}
+}
+
+func TestTemperature_Units(t *testing.T) {
+	temp := Temperature(250625) // 25.0625
+	if c := temp.Celsius(); c != 25.0625 {
+		t.Errorf("celsius: %v", c)
+	}
+	if f := temp.Fahrenheit(); f != 77.1125 {
+		t.Errorf("fahrenheit: %v", f)
+	}
+	if k := temp.Kelvin(); k != 298.2125 {
+		t.Errorf("kelvin: %v", k)
+	}
+	if c := temp.Centi(); c != 2506 {
+		t.Errorf("centi: %v", c)
+	}
+	if NewTemperatureFromCelsius(-10.125) != -101250 {
+		t.Errorf("from celsius: %v", NewTemperatureFromCelsius(-10.125))
+	}
+}
+
+func TestTemperature_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]Temperature{"t": -101250})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"t":-10.125}` {
+		t.Errorf("got: %s", data)
+	}
+}
@@ -0,0 +1,92 @@
+package digitemp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// Scratchpad layout and temperature format shared by thermometer families.
+type ThermometerLayout byte
+
+const (
+	// Temperature in 0.5ºC steps, COUNT_REMAIN and COUNT_PER_C for extended resolution,
+	// no configuration register. Used by DS18S20.
+	LayoutDS18S20 ThermometerLayout = iota
+	// Temperature in 1/16ºC steps and configuration register with R1 and R0 resolution bits.
+	// Used by DS18B20 and compatible devices.
+	LayoutDS18B20
+)
+
+var thermometers = struct {
+	mx      sync.RWMutex
+	layouts map[byte]ThermometerLayout
+}{
+	layouts: map[byte]ThermometerLayout{
+		FamilyDS18S20:  LayoutDS18S20,
+		FamilyDS1822:   LayoutDS18B20,
+		FamilyDS18B20:  LayoutDS18B20,
+		FamilyDS1825:   LayoutDS18B20,
+		FamilyDS28EA00: LayoutDS18B20,
+	},
+}
+
+// Register scratchpad layout of a thermometer family, so TemperatureSensor can handle it.
+// Fails if the family is already registered.
+func RegisterThermometerFamily(familyCode byte, layout ThermometerLayout) error {
+	if layout != LayoutDS18S20 && layout != LayoutDS18B20 {
+		return fmt.Errorf("unknown thermometer layout: %d", layout)
+	}
+
+	thermometers.mx.Lock()
+	defer thermometers.mx.Unlock()
+
+	if _, ok := thermometers.layouts[familyCode]; ok {
+		return fmt.Errorf("thermometer family 0x%02X is already registered", familyCode)
+	}
+	thermometers.layouts[familyCode] = layout
+	return nil
+}
+
+// Get scratchpad layout of the family. Returns false if the family is not a known thermometer.
+func thermometerLayout(familyCode byte) (ThermometerLayout, bool) {
+	thermometers.mx.RLock()
+	defer thermometers.mx.RUnlock()
+
+	layout, ok := thermometers.layouts[familyCode]
+	return layout, ok
+}
+
+// Check the family has configuration register with programmable resolution.
+func hasConfigRegister(familyCode byte) bool {
+	layout, ok := thermometerLayout(familyCode)
+	return ok && layout == LayoutDS18B20
+}
+
+// Check the family has DS18S20 scratchpad with COUNT_REMAIN and COUNT_PER_C.
+func hasCountRegisters(familyCode byte) bool {
+	layout, ok := thermometerLayout(familyCode)
+	return ok && layout == LayoutDS18S20
+}
+
+// Convert temperature from the scratchpad of the family in given resolution.
+// Returns temperature * 10000 ºC, 0 for unknown families.
+func convertTemperature(familyCode byte, resolution byte, scratchpad []byte) int {
+	layout, ok := thermometerLayout(familyCode)
+	if !ok {
+		return 0
+	}
+	t := int16(binary.LittleEndian.Uint16(scratchpad[0:2]))
+	switch layout {
+	case LayoutDS18S20:
+		countRemain := int(scratchpad[6])
+		countPerC := int(scratchpad[7])
+		if resolution == Resolution9bits || countPerC == 0 {
+			return int(t) * 5000
+		}
+		// extended resolution uses TEMP_READ with 0.5ºC bit truncated
+		return int(t>>1)*10000 - 2500 + 10000*(countPerC-countRemain)/countPerC
+	default:
+		return int(t) * 10000 / 16
+	}
+}
@@ -0,0 +1,30 @@
+package digitemp
+
+import "testing"
+
+func TestRegisterThermometerFamily(t *testing.T) {
+	if err := RegisterThermometerFamily(FamilyDS18B20, LayoutDS18B20); err == nil {
+		t.Error("duplicate family registered")
+	}
+	if err := RegisterThermometerFamily(0xfd, ThermometerLayout(7)); err == nil {
+		t.Error("unknown layout registered")
+	}
+	if hasConfigRegister(0xfd) || hasCountRegisters(0xfd) {
+		t.Error("unregistered family has registers")
+	}
+	if err := RegisterThermometerFamily(0xfd, LayoutDS18B20); err != nil {
+		t.Fatal(err)
+	}
+	if !hasConfigRegister(0xfd) || hasCountRegisters(0xfd) {
+		t.Error("registered family has wrong layout")
+	}
+
+	sensor := TemperatureSensor{familyCode: 0xfd}
+	sensor.updateResolution(Resolution10bits)
+	if sensor.precision != "10 bits" {
+		t.Errorf("got precision: %s", sensor.precision)
+	}
+	if temp := sensor.calcTemperature([]byte{0x91, 0x01}); temp != 250625 {
+		t.Errorf("got: %d, expected: 250625", temp)
+	}
+}
@@ -0,0 +1,89 @@
+package digitemp
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// User tag is a 16-bit value kept in TH (high byte) and TL (low byte) EEPROM bytes.
+// Installations which do not use alarms may store a sensor ID or a location code there
+// and move it to a replacement sensor by reprogramming.
+//
+// Note, that alarm thresholds share the same bytes. So, alarm search and user tags can't be used together.
+
+// Read user tag from EEPROM.
+// Scratchpad settings which are not saved to EEPROM (alarm thresholds, resolution) are preserved.
+func (s *TemperatureSensor) GetUserTag() (uint16, error) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	current, err := s.readDecodedScratchpad()
+	if err != nil {
+		return 0, err
+	}
+	if err := s.recallScratchpad(); err != nil {
+		return 0, err
+	}
+	stored, err := s.readDecodedScratchpad()
+	if err != nil {
+		return 0, err
+	}
+	// recall overwrites the scratchpad, so put unsaved settings back
+	if !bytes.Equal(stored.settings(), current.settings()) {
+		if err := s.writeVerifiedSettings(current); err != nil {
+			return 0, err
+		}
+	}
+	return userTag(stored), nil
+}
+
+// Write user tag to EEPROM and verify it's stored.
+func (s *TemperatureSensor) SetUserTag(tag uint16) error {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	sp, err := s.readDecodedScratchpad()
+	if err != nil {
+		return err
+	}
+	sp.High = int8(tag >> 8)
+	sp.Low = int8(tag & 0xff)
+	if err := s.writeVerifiedSettings(sp); err != nil {
+		return err
+	}
+	if err := s.copyScratchpad(); err != nil {
+		return err
+	}
+
+	// read it back from EEPROM
+	if err := s.recallScratchpad(); err != nil {
+		return err
+	}
+	if sp, err := s.readDecodedScratchpad(); err != nil {
+		return err
+	} else if t := userTag(sp); t != tag {
+		return fmt.Errorf("user tag verification failed (wrote: %d, read: %d)", tag, t)
+	}
+	return nil
+}
+
+// Read user tags of sensors and map tags to sensors.
+// Fails if any sensor can't be read or two sensors have the same tag.
+func MapUserTags(sensors []*TemperatureSensor) (map[uint16]*TemperatureSensor, error) {
+	tags := make(map[uint16]*TemperatureSensor, len(sensors))
+	for _, s := range sensors {
+		tag, err := s.GetUserTag()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.rom, err)
+		}
+		if other, ok := tags[tag]; ok {
+			return nil, fmt.Errorf("sensors %s and %s have the same user tag %d", other.rom, s.rom, tag)
+		}
+		tags[tag] = s
+	}
+	return tags, nil
+}
+
+func userTag(sp *Scratchpad) uint16 {
+	return uint16(byte(sp.High))<<8 | uint16(byte(sp.Low))
+}
@@ -0,0 +1,46 @@
+package digitemp
+
+import "testing"
+
+func TestTemperatureSensor_GetUserTag(t *testing.T) {
+	device := newFakeThermometer("2811223344556656")
+	device.eeprom = [3]byte{0x12, 0x34, 0x7f}
+	sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+	// unsaved settings
+	if err := sensor.Configure(30, 10, Resolution9bits, false); err != nil {
+		t.Fatal(err)
+	}
+	if tag, err := sensor.GetUserTag(); err != nil || tag != 0x1234 {
+		t.Errorf("got: 0x%04X, %v", tag, err)
+	}
+	if device.recalls != 1 {
+		t.Errorf("recalls: %d", device.recalls)
+	}
+	if sp := device.scratchpad; sp[2] != 30 || sp[3] != 10 || ConfigRegister(sp[4]).Resolution() != Resolution9bits {
+		t.Errorf("unsaved settings lost: % X", sp[2:5])
+	}
+	if sensor.GetResolution() != Resolution9bits {
+		t.Errorf("resolution: %d", sensor.GetResolution())
+	}
+}
+
+func TestTemperatureSensor_SetUserTag(t *testing.T) {
+	for _, parasitic := range []bool{false, true} {
+		device := newFakeThermometer("2811223344556656")
+		device.parasitic = parasitic
+		sensor := newFakeSensor(newFakeBus(device.fakeDevice), device)
+
+		if err := sensor.SetUserTag(0xabcd); err != nil {
+			t.Fatal(err)
+		}
+		if device.eeprom[0] != 0xab || device.eeprom[1] != 0xcd || device.recalls != 1 {
+			t.Errorf("parasitic %t: eeprom % X, recalls: %d", parasitic, device.eeprom, device.recalls)
+		}
+
+		device.copyFails = true
+		if err := sensor.SetUserTag(0x1234); err == nil {
+			t.Errorf("parasitic %t: EEPROM failure not detected", parasitic)
+		}
+	}
+}
@@ -14,3 +14,25 @@ func crc8(data []byte) byte {
 	}
 	return crc
 }
+
+// CRC16 used by 1-Wire devices (x^16 + x^15 + x^2 + 1).
+// Devices send it inverted, LSB first.
+func crc16(data []byte) uint16 {
+	var crc uint16 = 0x0000
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&0x01 > 0 {
+				crc = (crc >> 1) ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Check inverted CRC16 received from a device (2 bytes, LSB first) matches the data.
+func checkCRC16(data []byte, crc []byte) bool {
+	return ^crc16(data) == uint16(crc[0])|uint16(crc[1])<<8
+}
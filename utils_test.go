@@ -0,0 +1,18 @@
+package digitemp
+
+import "testing"
+
+func TestCRC8(t *testing.T) {
+	if crc := crc8([]byte{0x10, 0xa7, 0x5c, 0xa8, 0x02, 0x08, 0x00}); crc != 0x1a {
+		t.Errorf("got: 0x%02X", crc)
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	if crc := crc16([]byte("123456789")); crc != 0xbb3d {
+		t.Errorf("got: 0x%04X", crc)
+	}
+	if !checkCRC16([]byte("123456789"), []byte{0xc2, 0x44}) {
+		t.Error("inverted crc not accepted")
+	}
+}
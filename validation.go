@@ -0,0 +1,78 @@
+package digitemp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrPowerOnReset = errors.New("power-on reset value in scratchpad, no conversion happened")
+	ErrBogusReading = errors.New("bogus reading, bus is probably broken")
+	ErrOutOfRange   = errors.New("temperature is out of sensor's range")
+)
+
+// What to do when a sensor returns power-on reset value or bogus reading.
+type ValidationPolicy byte
+
+const (
+	ValidateNone   ValidationPolicy = iota // accept any reading
+	ValidateReport                         // return error
+	ValidateRetry                          // re-trigger conversion and return error if it persists
+)
+
+// Sensors' measurement range.
+const (
+	minTemperature Temperature = -55 * temperatureScale
+	maxTemperature Temperature = 125 * temperatureScale
+)
+
+// Set what to do when the sensor returns power-on reset value or bogus reading.
+// In ValidateRetry mode the conversion is re-triggered up to `retries` times.
+func (s *TemperatureSensor) SetValidationPolicy(policy ValidationPolicy, retries int) {
+	s.bus.lock()
+	defer s.bus.unlock()
+
+	s.validation = policy
+	s.validationRetries = retries
+}
+
+// Check if the error is returned by reading validation.
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrPowerOnReset) || errors.Is(err, ErrBogusReading) || errors.Is(err, ErrOutOfRange)
+}
+
+// Check scratchpad for power-on reset value, all-ones or all-zeros reads and out of range values.
+func (s *TemperatureSensor) validateScratchpad(scratchpad []byte) error {
+	if s.validation == ValidateNone {
+		return nil
+	}
+	// All ones or, with a bus held low, all zeros which pass CRC check as well.
+	if bytes.Count(scratchpad[0:8], []byte{0xff}) == 8 || bytes.Count(scratchpad[0:8], []byte{0x00}) == 8 {
+		return ErrBogusReading
+	}
+	// COUNT_PER_C is fixed to 10h, extended resolution divides by it
+	if hasCountRegisters(s.familyCode) && scratchpad[7] == 0 {
+		return ErrBogusReading
+	}
+
+	var t uint16
+	_ = binary.Read(bytes.NewReader(scratchpad), binary.LittleEndian, &t)
+
+	// Reserved bytes are untouched and keep their power-on values until the first conversion.
+	switch {
+	case hasCountRegisters(s.familyCode):
+		if t == 0x00aa && scratchpad[6] == 0x0c && scratchpad[7] == 0x10 {
+			return ErrPowerOnReset
+		}
+	case hasConfigRegister(s.familyCode):
+		if t == 0x0550 && scratchpad[5] == 0xff && scratchpad[6] == 0x0c && scratchpad[7] == 0x10 {
+			return ErrPowerOnReset
+		}
+	}
+
+	if temp := Temperature(s.calcTemperature(scratchpad)); temp < minTemperature || temp > maxTemperature {
+		return ErrOutOfRange
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package digitemp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const FamilyDS2401 = 0x01
+
+// Names of 16 wind directions clockwise from North.
+var WindDirections = [16]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// Converts anemometer's pulse rate to wind speed.
+// Speed in m/s = revolutions per second * SpeedPerRevolution + Offset, or 0 if the cups don't turn.
+type AnemometerCalibration struct {
+	PulsesPerRevolution float64
+	SpeedPerRevolution  float64 // m/s per revolution per second
+	Offset              float64 // m/s, the lowest speed which turns the cups
+}
+
+// Calibration of the original AAG anemometer: two pulses per revolution, 1 rev/s = 2.453 mph.
+var AAGAnemometer = AnemometerCalibration{
+	PulsesPerRevolution: 2,
+	SpeedPerRevolution:  1.0966,
+	Offset:              0,
+}
+
+// Wind speed in m/s for pulse rate in pulses per second.
+func (c AnemometerCalibration) Speed(rate float64) float64 {
+	if rate <= 0 || c.PulsesPerRevolution <= 0 {
+		return 0
+	}
+	return rate/c.PulsesPerRevolution*c.SpeedPerRevolution + c.Offset
+}
+
+// Devices and calibration of AAG/TAI8515 weather station.
+//
+// The wind vane is either DS2450 with resistor network (v3) or 8 DS2401 switched by a magnet (v1, v2).
+// Both need calibration which can't be detected: voltages of DS2450 channels for each of 16 directions,
+// or DS2401 ROM codes in order of vane positions N, NE, E, SE, S, SW, W, NW.
+type WeatherStationConfig struct {
+	Thermometer    *ROM // DS18S20
+	Counter        *ROM // DS2423
+	CounterChannel int  // DS2423CounterA or DS2423CounterB the anemometer is wired to
+	Anemometer     AnemometerCalibration
+	WindSampleTime time.Duration // time between counter samples if there is no previous one
+
+	VaneADC     *ROM           // DS2450
+	VaneTable   [16][4]float64 // voltages of channels A..D in V for directions clockwise from North
+	VaneOctet   [8]*ROM        // DS2401 for directions N, NE, E, SE, S, SW, W, NW
+	VanePowered bool           // DS2450 is powered from VCC
+}
+
+// Snapshot of weather station readings.
+type Weather struct {
+	Time          time.Time
+	Temperature   Temperature
+	WindSpeed     float64 // m/s
+	WindDirection int     // 0..15 clockwise from North, -1 if unknown
+}
+
+// Wind direction in degrees, NaN if unknown.
+func (w *Weather) WindHeading() float64 {
+	if w.WindDirection < 0 {
+		return math.NaN()
+	}
+	return float64(w.WindDirection) * 22.5
+}
+
+// Name of wind direction, e.g. NNE. Empty if unknown.
+func (w *Weather) WindDirectionName() string {
+	if w.WindDirection < 0 || w.WindDirection >= len(WindDirections) {
+		return ""
+	}
+	return WindDirections[w.WindDirection]
+}
+
+// AAG/TAI8515 1-Wire weather station.
+type WeatherStation struct {
+	bus         *UARTAdapter
+	config      WeatherStationConfig
+	thermometer *TemperatureSensor
+	counter     *DS2423
+	adc         *DS2450
+	rate        *CounterRate
+}
+
+// Find weather station devices on the bus: one DS18S20, one DS2423 and either one DS2450 or 1-2 DS2401.
+// Anemometer is assumed on counter A with AAG calibration. Vane calibration shall be filled in by the caller:
+// VaneTable for DS2450, or all 8 VaneOctet ROMs, which is left empty as the magnet connects
+// only one or two DS2401 at a time.
+func DetectWeatherStation(bus *UARTAdapter) (*WeatherStationConfig, error) {
+	roms, err := bus.GetConnectedROMs()
+	if err != nil {
+		return nil, err
+	}
+	return detectWeatherStation(roms)
+}
+
+func detectWeatherStation(roms []*ROM) (*WeatherStationConfig, error) {
+	families := make(map[byte][]*ROM)
+	for _, rom := range roms {
+		families[rom.Family()] = append(families[rom.Family()], rom)
+	}
+	if len(families[FamilyDS18S20]) != 1 || len(families[FamilyDS2423]) != 1 {
+		return nil, fmt.Errorf("weather station not found: %d DS18S20 and %d DS2423 present, 1 of each expected",
+			len(families[FamilyDS18S20]), len(families[FamilyDS2423]))
+	}
+	config := &WeatherStationConfig{
+		Thermometer:    families[FamilyDS18S20][0],
+		Counter:        families[FamilyDS2423][0],
+		CounterChannel: DS2423CounterA,
+		Anemometer:     AAGAnemometer,
+		WindSampleTime: time.Second,
+	}
+	switch {
+	case len(families[FamilyDS2450]) == 1:
+		config.VaneADC = families[FamilyDS2450][0]
+	case len(families[FamilyDS2401]) == 1 || len(families[FamilyDS2401]) == 2:
+		// octet vane, VaneADC stays nil
+	default:
+		return nil, fmt.Errorf("weather station vane not found: %d DS2450 and %d DS2401 present",
+			len(families[FamilyDS2450]), len(families[FamilyDS2401]))
+	}
+	return config, nil
+}
+
+// Create weather station from its configuration.
+func NewWeatherStation(bus *UARTAdapter, config *WeatherStationConfig) (*WeatherStation, error) {
+	if config == nil || config.Thermometer == nil || config.Counter == nil {
+		return nil, errors.New("weather station requires thermometer and counter")
+	}
+	if config.VaneADC != nil && config.VaneTable == [16][4]float64{} {
+		return nil, errors.New("weather station vane is not calibrated: VaneTable is empty")
+	}
+	if config.VaneADC == nil {
+		for n, rom := range config.VaneOctet {
+			if rom == nil || rom.Family() != FamilyDS2401 {
+				return nil, fmt.Errorf("weather station requires wind vane: DS2401 for %s missing", WindDirections[n*2])
+			}
+		}
+	}
+	w := &WeatherStation{
+		bus:    bus,
+		config: *config,
+		rate:   NewCounterRate(0),
+	}
+
+	var err error
+	if w.thermometer, err = NewTemperatureSensor(bus, config.Thermometer, true); err != nil {
+		return nil, err
+	}
+	if w.counter, err = NewDS2423(bus, config.Counter); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if config.VaneADC != nil {
+		if w.adc, err = NewDS2450(bus, config.VaneADC); err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+		if err = w.configureVane(); err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *WeatherStation) GetROM() *ROM {
+	return w.config.Counter
+}
+
+func (w *WeatherStation) GetFamilyCode() byte {
+	return FamilyDS2423
+}
+
+func (w *WeatherStation) GetName() string {
+	return "AAG/TAI8515 - Weather Station"
+}
+
+func (w *WeatherStation) Close() error {
+	if w.thermometer != nil {
+		return w.thermometer.Close()
+	}
+	return nil
+}
+
+// Read temperature, wind speed and direction.
+//
+// Wind speed is the average since the previous reading. On the first reading
+// the counter is sampled twice with WindSampleTime in between.
+func (w *WeatherStation) ReadWeather() (*Weather, error) {
+	t, err := w.thermometer.GetTemperatureValue()
+	if err != nil {
+		return nil, err
+	}
+	speed, err := w.readWindSpeed()
+	if err != nil {
+		return nil, err
+	}
+	direction, err := w.readWindDirection()
+	if err != nil {
+		return nil, err
+	}
+	return &Weather{
+		Time:          time.Now(),
+		Temperature:   t,
+		WindSpeed:     speed,
+		WindDirection: direction,
+	}, nil
+}
+
+func (w *WeatherStation) readWindSpeed() (float64, error) {
+	for {
+		value, err := w.counter.ReadCounter(w.config.CounterChannel)
+		if err != nil {
+			return 0, err
+		}
+		if rate, ok := w.rate.Update(value, time.Now()); ok {
+			return w.config.Anemometer.Speed(rate), nil
+		}
+		time.Sleep(w.config.WindSampleTime)
+	}
+}
+
+func (w *WeatherStation) readWindDirection() (int, error) {
+	if w.adc != nil {
+		voltages, err := w.adc.GetVoltages(0x0f, PresetNone)
+		if err != nil {
+			return -1, err
+		}
+		return vaneDirection(&w.config.VaneTable, voltages), nil
+	}
+
+	roms, err := w.bus.GetConnectedROMs()
+	if err != nil {
+		return -1, err
+	}
+	var present [8]bool
+	for _, rom := range roms {
+		for n, vane := range w.config.VaneOctet {
+			if vane != nil && *rom == *vane {
+				present[n] = true
+			}
+		}
+	}
+	return octetDirection(present)
+}
+
+// Configure DS2450 channels for the vane: 8 bits, 5.12V range.
+func (w *WeatherStation) configureVane() error {
+	for ch := 0; ch < 4; ch++ {
+		if err := w.adc.WriteChannelConfig(ch, &ADCChannelConfig{Resolution: 8, InputRange: 5.12}); err != nil {
+			return err
+		}
+	}
+	return w.adc.SetVCCPowered(w.config.VanePowered)
+}
+
+// Find direction with calibration voltages nearest to the measured ones.
+func vaneDirection(table *[16][4]float64, voltages [4]float64) int {
+	direction := -1
+	best := math.Inf(1)
+	for d, calibration := range table {
+		var distance float64
+		for ch, v := range calibration {
+			distance += (v - voltages[ch]) * (v - voltages[ch])
+		}
+		if distance < best {
+			direction, best = d, distance
+		}
+	}
+	return direction
+}
+
+// Magnet of the vane connects one DS2401 when pointing to one of 8 main directions,
+// or two neighbouring DS2401 when pointing between them.
+func octetDirection(present [8]bool) (int, error) {
+	var found []int
+	for n, p := range present {
+		if p {
+			found = append(found, n)
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0] * 2, nil
+	case len(found) == 2 && found[1]-found[0] == 1:
+		return found[0]*2 + 1, nil
+	case len(found) == 2 && found[0] == 0 && found[1] == 7:
+		return 15, nil
+	default:
+		return -1, fmt.Errorf("wrong wind vane state: %v", present)
+	}
+}
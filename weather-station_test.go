@@ -0,0 +1,137 @@
+package digitemp
+
+import "testing"
+
+func TestOctetDirection(t *testing.T) {
+	tests := []struct {
+		present   [8]bool
+		direction int
+	}{
+		{[8]bool{true}, 0},
+		{[8]bool{true, true}, 1},
+		{[8]bool{false, false, true}, 4},
+		{[8]bool{true, false, false, false, false, false, false, true}, 15},
+	}
+	for _, test := range tests {
+		if d, err := octetDirection(test.present); err != nil || d != test.direction {
+			t.Errorf("%v: got %d (%v), expected: %d", test.present, d, err, test.direction)
+		}
+	}
+	if _, err := octetDirection([8]bool{true, false, true}); err == nil {
+		t.Error("non-adjacent switches accepted")
+	}
+	if _, err := octetDirection([8]bool{}); err == nil {
+		t.Error("no switches accepted")
+	}
+}
+
+func TestVaneDirection(t *testing.T) {
+	var table [16][4]float64
+	for d := range table {
+		table[d] = [4]float64{float64(d) * 0.3, 4.5, 4.5, 4.5}
+	}
+	if d := vaneDirection(&table, [4]float64{3.05, 4.4, 4.6, 4.5}); d != 10 {
+		t.Errorf("got: %d, expected: 10", d)
+	}
+}
+
+func TestDetectWeatherStation(t *testing.T) {
+	roms := []*ROM{
+		{Code: [8]byte{FamilyDS18S20, 1}},
+		{Code: [8]byte{FamilyDS2423, 2}},
+		{Code: [8]byte{FamilyDS2450, 3}},
+	}
+	config, err := detectWeatherStation(roms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Thermometer != roms[0] || config.Counter != roms[1] || config.VaneADC != roms[2] {
+		t.Errorf("got: %+v", config)
+	}
+	if _, err := detectWeatherStation(roms[0:2]); err == nil {
+		t.Error("station without vane detected")
+	}
+}
+
+func TestDetectWeatherStation_Octet(t *testing.T) {
+	base := []*ROM{
+		{Code: [8]byte{FamilyDS18S20, 1}},
+		{Code: [8]byte{FamilyDS2423, 2}},
+	}
+	for switches := 0; switches <= 3; switches++ {
+		roms := append([]*ROM{}, base...)
+		for n := 0; n < switches; n++ {
+			roms = append(roms, &ROM{Code: [8]byte{FamilyDS2401, byte(n)}})
+		}
+		config, err := detectWeatherStation(roms)
+		if switches == 1 || switches == 2 {
+			if err != nil {
+				t.Errorf("%d DS2401: %v", switches, err)
+			} else if config.VaneADC != nil || config.VaneOctet != [8]*ROM{} {
+				t.Errorf("%d DS2401: got: %+v", switches, config)
+			}
+		} else if err == nil {
+			t.Errorf("%d DS2401: station detected", switches)
+		}
+	}
+}
+
+func TestNewWeatherStation_Vane(t *testing.T) {
+	config := &WeatherStationConfig{
+		Thermometer: &ROM{Code: [8]byte{FamilyDS18S20, 1}},
+		Counter:     &ROM{Code: [8]byte{FamilyDS2423, 2}},
+		VaneADC:     &ROM{Code: [8]byte{FamilyDS2450, 3}},
+	}
+	if _, err := NewWeatherStation(nil, config); err == nil {
+		t.Error("uncalibrated vane accepted")
+	}
+	config.VaneADC = nil
+	for n := 0; n < 7; n++ {
+		config.VaneOctet[n] = &ROM{Code: [8]byte{FamilyDS2401, byte(n)}}
+	}
+	if _, err := NewWeatherStation(nil, config); err == nil {
+		t.Error("incomplete octet accepted")
+	}
+}
+
+func TestWeatherStation_readWindDirection(t *testing.T) {
+	var config WeatherStationConfig
+	var devices []*fakeDevice
+	for n := range config.VaneOctet {
+		r := &ROM{Code: [8]byte{FamilyDS2401, 0x11, byte(n)}}
+		r.Code[7] = crc8(r.Code[:7])
+		config.VaneOctet[n] = r
+		devices = append(devices, &fakeDevice{rom: *r, absent: true})
+	}
+	w := &WeatherStation{bus: newFakeBus(devices...), config: config}
+
+	tests := []struct {
+		connected []int
+		direction int
+	}{
+		{[]int{2}, 4},
+		{[]int{2, 3}, 5},
+		{[]int{0, 7}, 15},
+	}
+	for _, test := range tests {
+		for _, d := range devices {
+			d.absent = true
+		}
+		for _, n := range test.connected {
+			devices[n].absent = false
+		}
+		if d, err := w.readWindDirection(); err != nil || d != test.direction {
+			t.Errorf("%v: got %d (%v), expected: %d", test.connected, d, err, test.direction)
+		}
+	}
+}
+
+func TestAnemometerSpeed(t *testing.T) {
+	if s := AAGAnemometer.Speed(4); s != 2*AAGAnemometer.SpeedPerRevolution {
+		t.Errorf("got: %f", s)
+	}
+	w := &Weather{WindDirection: 5}
+	if w.WindDirectionName() != "ESE" || w.WindHeading() != 112.5 {
+		t.Errorf("got: %s, %f", w.WindDirectionName(), w.WindHeading())
+	}
+}